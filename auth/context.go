@@ -0,0 +1,58 @@
+// Package auth carries the identity of the user making a request through its
+// context, from the http middlewares down to the services.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// The scopes limiting what an api key can do.
+const (
+	ScopePostsRead  = "posts:read"
+	ScopePostsWrite = "posts:write"
+	ScopeTagsAdmin  = "tags:admin"
+)
+
+// Scopes lists every scope an api key can be given.
+var Scopes = []string{ScopePostsRead, ScopePostsWrite, ScopeTagsAdmin}
+
+// Principal is the identity a request is made with.
+type Principal struct {
+	// UserID is the user the request is made on behalf of.
+	UserID int
+	// Role is the role of the user, it gives the permissions of the request.
+	Role Role
+	// APIKeyID is the api key the request is made with, it is zero for the
+	// requests made with a token.
+	APIKeyID int
+	// Scopes limits what the requests made with an api key can do.
+	Scopes []string
+}
+
+// HasScope tells whether the principal is allowed the scope, the requests
+// made with a token are not limited by scopes.
+func (p Principal) HasScope(scope string) bool {
+	return p.APIKeyID == 0 || slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx made on behalf of the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal the request is made with, it is false
+// for anonymous requests.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// UserID returns the id of the user making the request, it is zero for
+// anonymous requests.
+func UserID(ctx context.Context) int {
+	principal, _ := PrincipalFrom(ctx)
+	return principal.UserID
+}
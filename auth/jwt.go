@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for the tokens that cannot be trusted, wrapping
+// the reason why.
+var ErrInvalidToken = errors.New("invalid token")
+
+type (
+	// JWTVerifier verifies the HS256 and RS256 tokens, whose subject is the
+	// id of a user.
+	JWTVerifier struct {
+		hmacSecret []byte
+		rsaKey     *rsa.PublicKey
+		jwks       JWKS
+		parserOpts []jwt.ParserOption
+	}
+
+	JWTVerifierOption func(*JWTVerifier)
+
+	// JWKS holds the keys of a JSON Web Key Set by their id.
+	JWKS map[string]any
+)
+
+// WithHMACSecret verifies the HS256 tokens with the secret.
+func WithHMACSecret(secret []byte) JWTVerifierOption {
+	return func(jv *JWTVerifier) {
+		jv.hmacSecret = secret
+	}
+}
+
+// WithRSAPublicKey verifies the RS256 tokens with the key.
+func WithRSAPublicKey(key *rsa.PublicKey) JWTVerifierOption {
+	return func(jv *JWTVerifier) {
+		jv.rsaKey = key
+	}
+}
+
+// WithJWKS verifies the tokens having a kid header with the key of the set
+// with that id, the other tokens are verified with the secret or the public
+// key.
+func WithJWKS(jwks JWKS) JWTVerifierOption {
+	return func(jv *JWTVerifier) {
+		jv.jwks = jwks
+	}
+}
+
+// WithIssuer only accepts the tokens issued by the issuer.
+func WithIssuer(issuer string) JWTVerifierOption {
+	return func(jv *JWTVerifier) {
+		jv.parserOpts = append(jv.parserOpts, jwt.WithIssuer(issuer))
+	}
+}
+
+// WithAudience only accepts the tokens issued for the audience.
+func WithAudience(audience string) JWTVerifierOption {
+	return func(jv *JWTVerifier) {
+		jv.parserOpts = append(jv.parserOpts, jwt.WithAudience(audience))
+	}
+}
+
+func NewJWTVerifier(opts ...JWTVerifierOption) *JWTVerifier {
+	jv := &JWTVerifier{
+		parserOpts: []jwt.ParserOption{
+			jwt.WithValidMethods([]string{"HS256", "RS256"}),
+			jwt.WithExpirationRequired(),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(jv)
+	}
+
+	return jv
+}
+
+// Verify checks the signature and the claims of the token, returning the
+// principal it was issued to.
+func (jv *JWTVerifier) Verify(token string) (Principal, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, jv.key, jv.parserOpts...)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return Principal{}, fmt.Errorf("%w: subject is not a user id", ErrInvalidToken)
+	}
+
+	return Principal{UserID: userID}, nil
+}
+
+// key picks the key verifying the token. The type of the key has to match the
+// signing method, so that a public key can never be used as an HMAC secret.
+func (jv *JWTVerifier) key(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok && jv.jwks != nil {
+		key, ok := jv.jwks[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %s", kid)
+		}
+
+		return key, nil
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if jv.hmacSecret != nil {
+			return jv.hmacSecret, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if jv.rsaKey != nil {
+			return jv.rsaKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no key to verify %s tokens", token.Method.Alg())
+}
+
+// ReadRSAPublicKey reads a PEM encoded RSA public key.
+func ReadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.ParseRSAPublicKeyFromPEM(data)
+}
+
+// ReadJWKS reads a JSON Web Key Set file.
+func ReadJWKS(path string) (JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set, made of RSA and symmetric keys.
+func ParseJWKS(data []byte) (JWKS, error) {
+	set := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}{}
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := base64.RawURLEncoding
+	jwks := make(JWKS, len(set.Keys))
+	for _, key := range set.Keys {
+		switch key.Kty {
+		case "RSA":
+			n, err := encoding.DecodeString(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid modulus: %w", key.Kid, err)
+			}
+
+			e, err := encoding.DecodeString(key.E)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid exponent: %w", key.Kid, err)
+			}
+
+			jwks[key.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			k, err := encoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: invalid secret: %w", key.Kid, err)
+			}
+
+			jwks[key.Kid] = k
+		default:
+			return nil, fmt.Errorf("key %s: unsupported key type %s", key.Kid, key.Kty)
+		}
+	}
+
+	return jwks, nil
+}
@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/elangreza14/assetfindr-test/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestJWTVerifierSuite struct {
+	suite.Suite
+
+	secret []byte
+	rsaKey *rsa.PrivateKey
+}
+
+func (suite *TestJWTVerifierSuite) SetupSuite() {
+	suite.secret = []byte("secret")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.rsaKey = rsaKey
+}
+
+func TestJWTVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(TestJWTVerifierSuite))
+}
+
+func (suite *TestJWTVerifierSuite) sign(method jwt.SigningMethod, key any, kid string, claims jwt.RegisteredClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	suite.Require().NoError(err)
+	return signed
+}
+
+func (suite *TestJWTVerifierSuite) TestJWTVerifier_Verify() {
+	valid := jwt.RegisteredClaims{
+		Subject:   "7",
+		Issuer:    "assetfindr",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	noExpiry := valid
+	noExpiry.ExpiresAt = nil
+	notUser := valid
+	notUser.Subject = "jane"
+	otherIssuer := valid
+	otherIssuer.Issuer = "other"
+
+	publicKey := &suite.rsaKey.PublicKey
+	hs256 := NewJWTVerifier(WithHMACSecret(suite.secret), WithIssuer("assetfindr"))
+	rs256 := NewJWTVerifier(WithRSAPublicKey(publicKey), WithIssuer("assetfindr"))
+	jwks := NewJWTVerifier(WithJWKS(JWKS{"rsa": publicKey, "hmac": suite.secret}))
+
+	testCases := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+		valid    bool
+	}{
+		{"hs256", hs256, suite.sign(jwt.SigningMethodHS256, suite.secret, "", valid), true},
+		{"rs256", rs256, suite.sign(jwt.SigningMethodRS256, suite.rsaKey, "", valid), true},
+		{"jwks rsa", jwks, suite.sign(jwt.SigningMethodRS256, suite.rsaKey, "rsa", valid), true},
+		{"jwks hmac", jwks, suite.sign(jwt.SigningMethodHS256, suite.secret, "hmac", valid), true},
+		{"jwks unknown key", jwks, suite.sign(jwt.SigningMethodHS256, suite.secret, "other", valid), false},
+		{"wrong secret", hs256, suite.sign(jwt.SigningMethodHS256, []byte("other"), "", valid), false},
+		{"no rsa key", hs256, suite.sign(jwt.SigningMethodRS256, suite.rsaKey, "", valid), false},
+		{"public key as hmac secret", jwks, suite.sign(jwt.SigningMethodHS256, suite.secret, "rsa", valid), false},
+		{"hs512", hs256, suite.sign(jwt.SigningMethodHS512, suite.secret, "", valid), false},
+		{"expired", hs256, suite.sign(jwt.SigningMethodHS256, suite.secret, "", expired), false},
+		{"no expiry", hs256, suite.sign(jwt.SigningMethodHS256, suite.secret, "", noExpiry), false},
+		{"subject not a user", hs256, suite.sign(jwt.SigningMethodHS256, suite.secret, "", notUser), false},
+		{"other issuer", hs256, suite.sign(jwt.SigningMethodHS256, suite.secret, "", otherIssuer), false},
+		{"malformed", hs256, "token", false},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			principal, err := tc.verifier.Verify(tc.token)
+			if !tc.valid {
+				suite.ErrorIs(err, ErrInvalidToken)
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal(Principal{UserID: 7}, principal)
+		})
+	}
+}
+
+func (suite *TestJWTVerifierSuite) TestParseJWKS() {
+	encoding := base64.RawURLEncoding
+	publicKey := &suite.rsaKey.PublicKey
+
+	suite.Run("success", func() {
+		jwks, err := ParseJWKS([]byte(fmt.Sprintf(`{"keys":[
+			{"kty":"RSA","kid":"rsa","n":%q,"e":%q},
+			{"kty":"oct","kid":"hmac","k":%q}
+		]}`,
+			encoding.EncodeToString(publicKey.N.Bytes()),
+			encoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			encoding.EncodeToString(suite.secret))))
+		suite.NoError(err)
+		suite.Equal(JWKS{"rsa": publicKey, "hmac": suite.secret}, jwks)
+	})
+
+	suite.Run("error unsupported key type", func() {
+		_, err := ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"ec"}]}`))
+		suite.EqualError(err, "key ec: unsupported key type EC")
+	})
+}
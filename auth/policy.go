@@ -0,0 +1,106 @@
+package auth
+
+import "slices"
+
+// Role is what a user is allowed to do, given by the policy.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleAuthor Role = "author"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Permission is an operation restricted to some roles.
+type Permission string
+
+const (
+	PermCreatePost Permission = "posts:create"
+	// PermEditPost and PermDeletePost apply to the posts of the user and to
+	// the posts without author, while their "any" counterparts apply to the
+	// posts of other users.
+	PermEditPost      Permission = "posts:edit"
+	PermEditAnyPost   Permission = "posts:edit_any"
+	PermDeletePost    Permission = "posts:delete"
+	PermDeleteAnyPost Permission = "posts:delete_any"
+	// PermDeleteComment applies to the comments of the user, while
+	// PermDeleteAnyComment applies to the comments of other users.
+	PermCreateComment    Permission = "comments:create"
+	PermDeleteComment    Permission = "comments:delete"
+	PermDeleteAnyComment Permission = "comments:delete_any"
+	PermReactToPost      Permission = "posts:react"
+	PermManageTags       Permission = "tags:manage"
+	PermPurgeTrash       Permission = "trash:purge"
+	PermManageUsers      Permission = "users:manage"
+)
+
+// policy lists the permissions of each role, any other role has none.
+var policy = map[Role][]Permission{
+	RoleViewer: {
+		PermCreateComment,
+		PermDeleteComment,
+		PermReactToPost,
+	},
+	RoleAuthor: {
+		PermCreatePost,
+		PermEditPost,
+		PermDeletePost,
+		PermCreateComment,
+		PermDeleteComment,
+		PermReactToPost,
+	},
+	RoleEditor: {
+		PermCreatePost,
+		PermEditPost,
+		PermEditAnyPost,
+		PermDeletePost,
+		PermCreateComment,
+		PermDeleteComment,
+		PermDeleteAnyComment,
+		PermReactToPost,
+		PermManageTags,
+	},
+	RoleAdmin: {
+		PermCreatePost,
+		PermEditPost,
+		PermEditAnyPost,
+		PermDeletePost,
+		PermDeleteAnyPost,
+		PermCreateComment,
+		PermDeleteComment,
+		PermDeleteAnyComment,
+		PermReactToPost,
+		PermManageTags,
+		PermPurgeTrash,
+		PermManageUsers,
+	},
+}
+
+// Can tells whether the role has the permission.
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(policy[r], permission)
+}
+
+// Can tells whether the role of the principal has the permission, anonymous
+// principals have none.
+func (p Principal) Can(permission Permission) bool {
+	return p.Role.Can(permission)
+}
+
+// CanSeeAnyTrash tells whether the principal sees the deleted posts of every
+// user in the trash, the others only see theirs and the ones without author.
+func (p Principal) CanSeeAnyTrash() bool {
+	return p.Can(PermDeleteAnyPost) || p.Can(PermPurgeTrash)
+}
+
+// PostPermission picks the permission of the principal to change a post or a
+// comment of the author, own for the ones of the principal and the ones
+// without author, any for the ones of other users.
+func (p Principal) PostPermission(authorID *int, own, any Permission) Permission {
+	if authorID == nil || *authorID == p.UserID {
+		return own
+	}
+
+	return any
+}
@@ -0,0 +1,60 @@
+package auth_test
+
+import (
+	"testing"
+
+	. "github.com/elangreza14/assetfindr-test/auth"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestPolicySuite struct {
+	suite.Suite
+}
+
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(TestPolicySuite))
+}
+
+func (suite *TestPolicySuite) TestRole_Can() {
+	roles := []Role{RoleViewer, RoleAuthor, RoleEditor, RoleAdmin, Role(""), Role("owner")}
+	// matrix holds whether each of the roles above has the permission
+	matrix := map[Permission][]bool{
+		PermCreatePost:       {false, true, true, true, false, false},
+		PermEditPost:         {false, true, true, true, false, false},
+		PermEditAnyPost:      {false, false, true, true, false, false},
+		PermDeletePost:       {false, true, true, true, false, false},
+		PermDeleteAnyPost:    {false, false, false, true, false, false},
+		PermCreateComment:    {true, true, true, true, false, false},
+		PermDeleteComment:    {true, true, true, true, false, false},
+		PermDeleteAnyComment: {false, false, true, true, false, false},
+		PermReactToPost:      {true, true, true, true, false, false},
+		PermManageTags:       {false, false, true, true, false, false},
+		PermPurgeTrash:       {false, false, false, true, false, false},
+		PermManageUsers:      {false, false, false, true, false, false},
+	}
+
+	for permission, allowed := range matrix {
+		for i, role := range roles {
+			suite.Run(string(permission)+" "+string(role), func() {
+				suite.Equal(allowed[i], role.Can(permission))
+				suite.Equal(allowed[i], Principal{UserID: 7, Role: role}.Can(permission))
+			})
+		}
+	}
+}
+
+func (suite *TestPolicySuite) TestPrincipal_CanSeeAnyTrash() {
+	suite.False(Principal{UserID: 7}.CanSeeAnyTrash())
+	suite.False(Principal{UserID: 7, Role: RoleAuthor}.CanSeeAnyTrash())
+	suite.False(Principal{UserID: 7, Role: RoleEditor}.CanSeeAnyTrash())
+	suite.True(Principal{UserID: 7, Role: RoleAdmin}.CanSeeAnyTrash())
+}
+
+func (suite *TestPolicySuite) TestPrincipal_PostPermission() {
+	principal := Principal{UserID: 7, Role: RoleAuthor}
+	own, other := 7, 8
+
+	suite.Equal(PermEditPost, principal.PostPermission(&own, PermEditPost, PermEditAnyPost))
+	suite.Equal(PermEditPost, principal.PostPermission(nil, PermEditPost, PermEditAnyPost))
+	suite.Equal(PermEditAnyPost, principal.PostPermission(&other, PermEditPost, PermEditAnyPost))
+}
@@ -2,20 +2,27 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
 	"github.com/elangreza14/assetfindr-test/controller"
 	"github.com/elangreza14/assetfindr-test/model"
+	"github.com/elangreza14/assetfindr-test/normalizer"
 	"github.com/elangreza14/assetfindr-test/repository"
 	"github.com/elangreza14/assetfindr-test/service"
+	"github.com/elangreza14/assetfindr-test/storage"
 	"github.com/gin-contrib/cors"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
@@ -26,6 +33,10 @@ import (
 )
 
 func main() {
+	migrateTagsOnly := flag.Bool("migrate-tags", false, "normalize and merge the duplicated tags, then exit")
+	renderPostsOnly := flag.Bool("render-posts", false, "render the posts written before their html was stored, then exit")
+	flag.Parse()
+
 	// setup env
 	err := godotenv.Load()
 	errChecker(err)
@@ -39,20 +50,113 @@ func main() {
 	db, err := Db()
 	errChecker(err)
 
+	tagNormalizer := normalizer.NewTagNormalizer(normalizer.CaseMode(os.Getenv("TAG_LABEL_CASE")))
+
+	if *migrateTagsOnly {
+		err = migrateTags(db, tagNormalizer, logger)
+		errChecker(err)
+		return
+	}
+
+	if *renderPostsOnly {
+		err = renderPosts(db, logger)
+		errChecker(err)
+		return
+	}
+
+	err = Migrate(db)
+	errChecker(err)
+
+	// orphan tags are either removed by the transaction detaching them, or
+	// periodically by the sweeper
+	orphanTagPolicy := os.Getenv("ORPHAN_TAG_POLICY")
+	postRepositoryOpts := []repository.PostRepositoryOption{}
+	if orphanTagPolicy == "transaction" {
+		postRepositoryOpts = append(postRepositoryOpts, repository.WithOrphanTagCleanup())
+	}
+
 	// dependency injection
-	postRepository := repository.NewPostRepository(db)
-	postService := service.NewPostService(postRepository)
-	postController := controller.NewPostController(postService)
+	postRepository := repository.NewPostRepository(db, postRepositoryOpts...)
+	trashRetention, err := durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	errChecker(err)
+	postServiceOpts := []service.PostServiceOption{
+		service.WithCursorSecret([]byte(os.Getenv("CURSOR_SECRET"))),
+		service.WithPostTagNormalizer(tagNormalizer),
+		service.WithTrashRetention(trashRetention),
+	}
+	if os.Getenv("POST_SLUG_POLICY") == "regenerate" {
+		postServiceOpts = append(postServiceOpts, service.WithSlugRegeneration())
+	}
+	postService := service.NewPostService(postRepository, postServiceOpts...)
+
+	postControllerOpts := []controller.PostControllerOption{}
+	if cacheControl := os.Getenv("POST_CACHE_CONTROL"); cacheControl != "" {
+		postControllerOpts = append(postControllerOpts, controller.WithCacheControl(cacheControl))
+	}
+	postController := controller.NewPostController(postService, postControllerOpts...)
+	tagRepository := repository.NewTagRepository(db)
+	tagService := service.NewTagService(tagRepository, service.WithTagNormalizer(tagNormalizer))
+	tagController := controller.NewTagController(tagService)
+	userRepository := repository.NewUserRepository(db)
+	userService := service.NewUserService(userRepository)
+	userController := controller.NewUserController(userService)
+	commentService := service.NewCommentService(repository.NewCommentRepository(db), postRepository)
+	commentController := controller.NewCommentController(commentService)
+	reactionService := service.NewReactionService(repository.NewReactionRepository(db), postRepository)
+	reactionController := controller.NewReactionController(reactionService)
+	blobStore, err := BlobStore()
+	errChecker(err)
+	attachmentServiceOpts := []service.AttachmentServiceOption{}
+	if maxSize := os.Getenv("ATTACHMENT_MAX_SIZE"); maxSize != "" {
+		size, err := strconv.ParseInt(maxSize, 10, 64)
+		errChecker(err)
+		attachmentServiceOpts = append(attachmentServiceOpts, service.WithMaxAttachmentSize(size))
+	}
+	attachmentService := service.NewAttachmentService(repository.NewAttachmentRepository(db), postRepository, blobStore, attachmentServiceOpts...)
+	attachmentController := controller.NewAttachmentController(attachmentService)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db))
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	jwtVerifier, err := JWTVerifier()
+	errChecker(err)
+
+	// background jobs
+	jobs := []*service.PeriodicJob{}
+	if orphanTagPolicy == "sweeper" {
+		interval, err := durationEnv("ORPHAN_TAG_SWEEP_INTERVAL", time.Hour)
+		errChecker(err)
+		jobs = append(jobs, service.NewPeriodicJob("orphan tag sweeper", interval, logger, tagService.SweepOrphanTags))
+	}
+
+	trashPurgeInterval, err := durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+	errChecker(err)
+	jobs = append(jobs, service.NewPeriodicJob("trash purger", trashPurgeInterval, logger, postService.PurgeTrash))
+
+	// the attachments of the purged posts are deleted along with their blobs,
+	// which cannot be part of the purge transaction
+	attachmentSweepInterval, err := durationEnv("ATTACHMENT_SWEEP_INTERVAL", time.Hour)
+	errChecker(err)
+	jobs = append(jobs, service.NewPeriodicJob("attachment sweeper", attachmentSweepInterval, logger, attachmentService.SweepOrphanAttachments))
+
+	// every instance runs the publisher, the due posts are locked so that
+	// each of them is only published once
+	publishInterval, err := durationEnv("POST_PUBLISH_INTERVAL", time.Minute)
+	errChecker(err)
+	jobs = append(jobs, service.NewPeriodicJob("post publisher", publishInterval, logger, postService.PublishScheduledPosts))
 
 	// router
 	if os.Getenv("ENV") != "DEVELOPMENT" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	router := gin.New()
+	// the services read the user making the request from the context of
+	// the request, through the gin context they are given
+	router.ContextWithFallback = true
 
 	// cors middleware
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{fmt.Sprintf("http://localhost%s", os.Getenv("HTTP_PORT"))}
+	config.AddAllowHeaders("If-Match", "If-None-Match", "If-Modified-Since", "Range", "Authorization", "X-API-Key")
+	config.AddExposeHeaders("ETag", "Last-Modified", "Content-Range", "Content-Disposition")
 	router.Use(cors.New(config))
 
 	// logger middleware
@@ -64,9 +168,19 @@ func main() {
 		c.String(http.StatusOK, "pong")
 	})
 
-	// group api
+	// group api, the reads are public while the writes need a bearer token
+	// or an api key
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	apiGroup.Use(controller.Authenticate(jwtVerifier, userService))
+	apiGroup.Use(apiKeyController.Authenticate())
+	authenticatedGroup := apiGroup.Group("", controller.RequireAuth())
+	routes.UserRoute(apiGroup, authenticatedGroup, userController)
+	routes.APIKeyRoute(authenticatedGroup, apiKeyController)
+	routes.PostRoute(apiGroup, authenticatedGroup, postController)
+	routes.TagRoute(apiGroup, authenticatedGroup, tagController)
+	routes.CommentRoute(apiGroup, authenticatedGroup, commentController)
+	routes.ReactionRoute(authenticatedGroup, reactionController)
+	routes.AttachmentRoute(apiGroup, authenticatedGroup, attachmentController)
 
 	srv := &http.Server{
 		Addr:    os.Getenv("HTTP_PORT"),
@@ -79,11 +193,42 @@ func main() {
 		}
 	}()
 
+	// the metrics are served on an address of their own, meant to be only
+	// reachable internally, and not served at all when it is not set
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/vars", expvar.Handler())
+	debugSrv := &http.Server{
+		Addr:    os.Getenv("DEBUG_ADDR"),
+		Handler: debugMux,
+	}
+
+	if debugSrv.Addr != "" {
+		go func() {
+			if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("listen debug: %s\n", err)
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		job.Start()
+	}
+
 	wait := gracefulShutdown(context.Background(), logger, time.Second*5,
 		func(ctx context.Context) error {
 			return srv.Shutdown(ctx)
 		},
 		func(ctx context.Context) error {
+			return debugSrv.Shutdown(ctx)
+		},
+		func(ctx context.Context) error {
+			// jobs are stopped before closing the db they are using
+			for _, job := range jobs {
+				if err := job.Stop(ctx); err != nil {
+					return err
+				}
+			}
+
 			sqlDB, _ := db.DB()
 			return sqlDB.Close()
 		})
@@ -97,6 +242,17 @@ func errChecker(err error) {
 	}
 }
 
+// durationEnv parses the env key as a time.Duration, falling back to def when
+// it is not set.
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
 func Db() (*gorm.DB, error) {
 	conn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		os.Getenv("POSTGRES_USER"),
@@ -112,12 +268,149 @@ func Db() (*gorm.DB, error) {
 		return nil, err
 	}
 
-	err = db.AutoMigrate(model.Post{}, model.Tag{})
+	return db, nil
+}
+
+// JWTVerifier verifies the bearer tokens with the HS256 secret, the RS256
+// public key and the JWKS file configured, at least one of them is required.
+func JWTVerifier() (*auth.JWTVerifier, error) {
+	opts := []auth.JWTVerifierOption{}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		opts = append(opts, auth.WithHMACSecret([]byte(secret)))
+	}
+
+	if path := os.Getenv("JWT_PUBLIC_KEY_FILE"); path != "" {
+		key, err := auth.ReadRSAPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, auth.WithRSAPublicKey(key))
+	}
+
+	if path := os.Getenv("JWT_JWKS_FILE"); path != "" {
+		jwks, err := auth.ReadJWKS(path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, auth.WithJWKS(jwks))
+	}
+
+	if len(opts) == 0 {
+		return nil, errors.New("JWT_SECRET, JWT_PUBLIC_KEY_FILE or JWT_JWKS_FILE is required")
+	}
+
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		opts = append(opts, auth.WithIssuer(issuer))
+	}
+
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		opts = append(opts, auth.WithAudience(audience))
+	}
+
+	return auth.NewJWTVerifier(opts...), nil
+}
+
+// BlobStore is the store of the content of the attachments, a directory of
+// the local disk by default or an S3 compatible bucket when BLOB_STORE is s3.
+func BlobStore() (service.IBlobStore, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "attachments"
+		}
+		return storage.NewLocalStore(dir)
+	case "s3":
+		opts := []storage.S3StoreOption{
+			storage.WithS3Credentials(os.Getenv("S3_ACCESS_KEY_ID"), os.Getenv("S3_SECRET_ACCESS_KEY")),
+		}
+		if region := os.Getenv("S3_REGION"); region != "" {
+			opts = append(opts, storage.WithS3Region(region))
+		}
+		if os.Getenv("S3_VIRTUAL_HOSTED_STYLE") == "true" {
+			opts = append(opts, storage.WithS3VirtualHostedStyle())
+		}
+		return storage.NewS3Store(os.Getenv("S3_ENDPOINT"), os.Getenv("S3_BUCKET"), opts...)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q, either local or s3", os.Getenv("BLOB_STORE"))
+	}
+}
+
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(model.User{}, model.APIKey{}, model.Post{}, model.PostSlug{}, model.Tag{}, model.PostRevision{}, model.Comment{}, model.Reaction{}, model.PostReactionCount{}, model.Attachment{})
+}
+
+// migrateTags is the one-off migration of the tags created before labels
+// were normalized. Their duplicated labels are merged, so that the case
+// insensitive unique index of the labels can be created.
+func migrateTags(db *gorm.DB, tagNormalizer normalizer.TagNormalizer, logger *zap.Logger) error {
+	if db.Migrator().HasTable(&model.Tag{}) {
+		merged, err := normalizeLegacyTags(db, tagNormalizer)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("tags migrated", zap.Int("merged", merged))
+	}
+
+	return Migrate(db)
+}
+
+// normalizeLegacyTags merges and renames the tags of a database that is not
+// migrated yet. Migrate cannot run before, as the posts pull in the unique
+// index of the labels, so only the columns touched while normalizing are
+// added beforehand.
+func normalizeLegacyTags(db *gorm.DB, tagNormalizer normalizer.TagNormalizer) (int, error) {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&model.Tag{}, "Slug") {
+		err := migrator.AddColumn(&model.Tag{}, "Slug")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// the posts of the renamed and merged tags get their version bumped
+	for _, field := range []string{"Version", "UpdatedAt"} {
+		if !migrator.HasColumn(&model.Post{}, field) {
+			err := migrator.AddColumn(&model.Post{}, field)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	tagService := service.NewTagService(repository.NewTagRepository(db), service.WithTagNormalizer(tagNormalizer))
+	merged, err := tagService.NormalizeTags(context.Background())
 	if err != nil {
-		return nil, err
+		return merged, err
 	}
 
-	return db, nil
+	if migrator.HasIndex(&model.Tag{}, "idx_label_tag") {
+		err = migrator.DropIndex(&model.Tag{}, "idx_label_tag")
+		if err != nil {
+			return merged, err
+		}
+	}
+
+	return merged, nil
+}
+
+// renderPosts is the one-off migration of the posts written before their html,
+// excerpt, word count and reading time were stored, which are rendered once.
+func renderPosts(db *gorm.DB, logger *zap.Logger) error {
+	err := Migrate(db)
+	if err != nil {
+		return err
+	}
+
+	postService := service.NewPostService(repository.NewPostRepository(db))
+	rendered, err := postService.RenderLegacyPosts(context.Background())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("posts rendered", zap.Int("rendered", rendered))
+	return nil
 }
 
 func Logger() (*zap.Logger, error) {
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/normalizer"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type TestMigrateSuite struct {
+	suite.Suite
+
+	sqlDB  *sql.DB
+	gormDB *gorm.DB
+	mock   sqlmock.Sqlmock
+}
+
+func (suite *TestMigrateSuite) SetupTest() {
+	sqlDB, mock, err := sqlmock.New()
+	suite.Require().NoError(err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 sqlDB,
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	suite.Require().NoError(err)
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+}
+
+func (suite *TestMigrateSuite) TearDownTest() {
+	suite.sqlDB.Close()
+}
+
+func TestMigrateTestSuite(t *testing.T) {
+	suite.Run(t, new(TestMigrateSuite))
+}
+
+func (suite *TestMigrateSuite) expectHasColumn(table, column string, has bool) {
+	count := 0
+	if has {
+		count = 1
+	}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = $1 AND column_name = $2`)).
+		WithArgs(table, column).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func (suite *TestMigrateSuite) TestMigrate_NormalizeLegacyTags() {
+	suite.Run("baseline schema", func() {
+		// the baseline posts only have an id, a title and a content, the
+		// columns touched by the merge are added before it
+		suite.expectHasColumn("tags", "slug", false)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "tags" ADD "slug" text`)).
+			WillReturnResult(driver.ResultNoRows)
+		suite.expectHasColumn("posts", "version", false)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "posts" ADD "version" bigint NOT NULL DEFAULT 1`)).
+			WillReturnResult(driver.ResultNoRows)
+		suite.expectHasColumn("posts", "updated_at", false)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "posts" ADD "updated_at" timestamptz NOT NULL DEFAULT now()`)).
+			WillReturnResult(driver.ResultNoRows)
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" ORDER BY id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "slug"}).
+				AddRow(1, "go", "").
+				AddRow(2, "Go", ""))
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`)).
+			WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") SELECT post_id, $1 FROM post_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE tag_id=$1;`)).
+			WithArgs(2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(2).WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`)).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "label"=$1,"slug"=$2 WHERE "id" = $3`)).
+			WithArgs("go", "go", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM pg_indexes WHERE tablename = $1 AND indexname = $2 AND schemaname = CURRENT_SCHEMA()`)).
+			WithArgs("tags", "idx_label_tag").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DROP INDEX "idx_label_tag"`)).
+			WillReturnResult(driver.ResultNoRows)
+
+		merged, err := normalizeLegacyTags(suite.gormDB, normalizer.NewTagNormalizer(normalizer.CaseLower))
+		suite.NoError(err)
+		suite.Equal(1, merged)
+		suite.NoError(suite.mock.ExpectationsWereMet())
+	})
+
+	suite.Run("migrated schema", func() {
+		suite.expectHasColumn("tags", "slug", true)
+		suite.expectHasColumn("posts", "version", true)
+		suite.expectHasColumn("posts", "updated_at", true)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" ORDER BY id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "slug"}).
+				AddRow(1, "go", "go"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM pg_indexes WHERE tablename = $1 AND indexname = $2 AND schemaname = CURRENT_SCHEMA()`)).
+			WithArgs("tags", "idx_label_tag").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		merged, err := normalizeLegacyTags(suite.gormDB, normalizer.NewTagNormalizer(normalizer.CaseLower))
+		suite.NoError(err)
+		suite.Zero(merged)
+		suite.NoError(suite.mock.ExpectationsWereMet())
+	})
+}
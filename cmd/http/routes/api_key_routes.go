@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func APIKeyRoute(authenticated *gin.RouterGroup, apiKeyController *controller.APIKeyController) {
+	apiKeyRoutes := authenticated.Group("/api-keys")
+	apiKeyRoutes.POST("", apiKeyController.CreateAPIKey())
+	apiKeyRoutes.GET("", apiKeyController.GetAPIKeys())
+	apiKeyRoutes.DELETE("/:id", apiKeyController.RevokeAPIKey())
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentRoute mounts the listing and the downloads of the attachments on
+// public and their uploads on authenticated. The api keys need the posts:read
+// and posts:write scopes respectively, and the roles are checked by the
+// service once the post is known.
+func AttachmentRoute(public, authenticated *gin.RouterGroup, attachmentController *controller.AttachmentController) {
+	attachmentRoutes := public.Group("/posts/:id/attachments", controller.RequireScope(auth.ScopePostsRead))
+	attachmentRoutes.GET("", attachmentController.GetAttachments())
+	attachmentRoutes.GET("/:attachment_id", attachmentController.DownloadAttachment())
+
+	authenticatedAttachmentRoutes := authenticated.Group("/posts/:id/attachments", controller.RequireScope(auth.ScopePostsWrite))
+	authenticatedAttachmentRoutes.POST("", attachmentController.UploadAttachment())
+	authenticatedAttachmentRoutes.DELETE("/:attachment_id", attachmentController.DeleteAttachment())
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentRoute mounts the threads of the posts on public and the writes of
+// the comments on authenticated. The api keys need the posts:read and
+// posts:write scopes respectively.
+func CommentRoute(public, authenticated *gin.RouterGroup, commentController *controller.CommentController) {
+	commentRoutes := public.Group("/posts/:id/comments", controller.RequireScope(auth.ScopePostsRead))
+	commentRoutes.GET("", commentController.GetComments())
+
+	authenticatedCommentRoutes := authenticated.Group("/posts/:id/comments", controller.RequireScope(auth.ScopePostsWrite))
+	authenticatedCommentRoutes.POST("", controller.RequirePermission(auth.PermCreateComment), commentController.CreateComment())
+	authenticatedCommentRoutes.DELETE("/:comment_id", commentController.DeleteComment())
+}
@@ -1,15 +1,35 @@
 package routes
 
 import (
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/controller"
 	"github.com/gin-gonic/gin"
 )
 
-func PostRoute(route *gin.RouterGroup, postController *controller.PostController) {
-	postRoutes := route.Group("/posts")
+// PostRoute mounts the reads of the posts on public and their writes, along
+// with the trash, on authenticated. The api keys need the posts:read and
+// posts:write scopes respectively, and the roles are checked by the service
+// once the post is known.
+func PostRoute(public, authenticated *gin.RouterGroup, postController *controller.PostController) {
+	postRoutes := public.Group("/posts", controller.RequireScope(auth.ScopePostsRead))
 	postRoutes.GET("", postController.GetPosts())
-	postRoutes.POST("", postController.CreatePost())
+	postRoutes.GET("/search", postController.SearchPosts())
+	postRoutes.GET("/by-slug/:slug", postController.GetPostBySlug())
 	postRoutes.GET("/:id", postController.GetPost())
-	postRoutes.PUT("/:id", postController.UpdatePost())
-	postRoutes.DELETE("/:id", postController.DeletePost())
+	postRoutes.GET("/:id/revisions", postController.GetPostRevisions())
+	postRoutes.GET("/:id/revisions/diff", postController.DiffPostRevisions())
+	postRoutes.GET("/:id/revisions/:rev", postController.GetPostRevision())
+
+	authenticatedPostRoutes := authenticated.Group("/posts", controller.RequireScope(auth.ScopePostsWrite))
+	authenticatedPostRoutes.POST("", controller.RequirePermission(auth.PermCreatePost), postController.CreatePost())
+	authenticatedPostRoutes.GET("/trash", postController.GetTrashedPosts())
+	authenticatedPostRoutes.PUT("/:id", postController.UpdatePost())
+	authenticatedPostRoutes.PATCH("/:id", postController.PatchPost())
+	authenticatedPostRoutes.DELETE("/:id", postController.DeletePost())
+	authenticatedPostRoutes.POST("/:id/publish", postController.UpdatePostStatus("published"))
+	authenticatedPostRoutes.POST("/:id/unpublish", postController.UpdatePostStatus("draft"))
+	authenticatedPostRoutes.POST("/:id/archive", postController.UpdatePostStatus("archived"))
+	authenticatedPostRoutes.POST("/:id/restore", postController.RestorePost())
+	authenticatedPostRoutes.DELETE("/:id/purge", controller.RequirePermission(auth.PermPurgeTrash), postController.PurgePost())
+	authenticatedPostRoutes.POST("/:id/revisions/:rev/restore", postController.RestorePostRevision())
 }
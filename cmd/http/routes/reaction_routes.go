@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// ReactionRoute mounts the reactions to the posts on authenticated, the api
+// keys need the posts:write scope.
+func ReactionRoute(authenticated *gin.RouterGroup, reactionController *controller.ReactionController) {
+	reactionRoutes := authenticated.Group(
+		"/posts/:id/reactions",
+		controller.RequireScope(auth.ScopePostsWrite),
+		controller.RequirePermission(auth.PermReactToPost),
+	)
+	reactionRoutes.POST("/:kind", reactionController.AddReaction())
+	reactionRoutes.DELETE("/:kind", reactionController.RemoveReaction())
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// TagRoute mounts the reads of the tags on public and their writes on
+// authenticated. The api keys need the posts:read and tags:admin scopes
+// respectively, and the writes need a role allowed to manage tags.
+func TagRoute(public, authenticated *gin.RouterGroup, tagController *controller.TagController) {
+	tagRoutes := public.Group("/tags", controller.RequireScope(auth.ScopePostsRead))
+	tagRoutes.GET("", tagController.GetTags())
+	tagRoutes.GET("/:id", tagController.GetTag())
+	tagRoutes.GET("/:id/posts", tagController.GetTagPosts())
+
+	authenticatedTagRoutes := authenticated.Group(
+		"/tags",
+		controller.RequireScope(auth.ScopeTagsAdmin),
+		controller.RequirePermission(auth.PermManageTags),
+	)
+	authenticatedTagRoutes.PATCH("/:id", tagController.UpdateTag())
+	authenticatedTagRoutes.POST("/:id/merge", tagController.MergeTag())
+	authenticatedTagRoutes.DELETE("/:id", tagController.DeleteTag())
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// UserRoute mounts the sign up and the profiles of the users on public, and
+// the change of their role, reserved to the admins signed in with a token, on
+// authenticated.
+func UserRoute(public, authenticated *gin.RouterGroup, userController *controller.UserController) {
+	userRoutes := public.Group("/users")
+	userRoutes.POST("", userController.CreateUser())
+	userRoutes.GET("/:id", userController.GetUser())
+
+	authenticatedUserRoutes := authenticated.Group("/users")
+	authenticatedUserRoutes.PUT("/:id/role",
+		controller.RequireToken(),
+		controller.RequirePermission(auth.PermManageUsers),
+		userController.UpdateUserRole(),
+	)
+}
@@ -0,0 +1,116 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IAPIKeyService interface {
+		CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error)
+		GetAPIKeys(ctx context.Context, req dto.GetAPIKeysRequest) ([]dto.GetAPIKeyResponse, *dto.Pagination, error)
+		RevokeAPIKey(ctx context.Context, id int) error
+		AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error)
+	}
+
+	APIKeyController struct {
+		apiKeyService IAPIKeyService
+	}
+)
+
+func NewAPIKeyController(apiKeyService IAPIKeyService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (ac *APIKeyController) CreateAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.CreateAPIKeyRequest{}
+		err := c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		apiKey, err := ac.apiKeyService.CreateAPIKey(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, dto.NewBaseResponse(apiKey, nil))
+	}
+}
+
+func (ac *APIKeyController) GetAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.GetAPIKeysRequest{}
+		err := c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		apiKeys, pagination, err := ac.apiKeyService.GetAPIKeys(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(apiKeys, *pagination))
+	}
+}
+
+func (ac *APIKeyController) RevokeAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriAPIKeyRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = ac.apiKeyService.RevokeAPIKey(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("revoked", nil))
+	}
+}
+
+// Authenticate makes the request on behalf of the user of the key sent in
+// the X-API-Key header, limited to the scopes of the key. Requests without
+// the header are left to the bearer tokens, while sending both is rejected.
+func (ac *APIKeyController) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if _, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+			abortUnauthorized(c, dto.ErrorUnauthorized{Message: "use either a bearer token or an api key"})
+			return
+		}
+
+		principal, err := ac.apiKeyService.AuthenticateAPIKey(c, key)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
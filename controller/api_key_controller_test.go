@@ -0,0 +1,184 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	APIKeyController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestAPIKeyControllerSuite struct {
+	suite.Suite
+
+	Ctrl              *gomock.Controller
+	MockAPIKeyService *APIKeyController.MockIAPIKeyService
+	MockPostService   *APIKeyController.MockIPostService
+	MockUserService   *APIKeyController.MockIUserService
+	router            *gin.Engine
+	token             string
+}
+
+func (suite *TestAPIKeyControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockAPIKeyService = APIKeyController.NewMockIAPIKeyService(suite.Ctrl)
+	suite.MockPostService = APIKeyController.NewMockIPostService(suite.Ctrl)
+	suite.MockUserService = APIKeyController.NewMockIUserService(suite.Ctrl)
+	suite.MockUserService.EXPECT().GetUser(gomock.Any(), 7).Return(&dto.GetUserResponse{ID: 7, Role: "author"}, nil).AnyTimes()
+	apiKeyController := controller.NewAPIKeyController(suite.MockAPIKeyService)
+
+	secret := []byte("secret")
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "7",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(secret)
+	suite.Require().NoError(err)
+	suite.token = token
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	apiGroup.Use(controller.Authenticate(auth.NewJWTVerifier(auth.WithHMACSecret(secret)), suite.MockUserService))
+	apiGroup.Use(apiKeyController.Authenticate())
+	authenticatedGroup := apiGroup.Group("", controller.RequireAuth())
+	routes.APIKeyRoute(authenticatedGroup, apiKeyController)
+	routes.PostRoute(apiGroup, authenticatedGroup, controller.NewPostController(suite.MockPostService))
+}
+
+func (suite *TestAPIKeyControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestAPIKeyControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAPIKeyControllerSuite))
+}
+
+func (suite *TestAPIKeyControllerSuite) serve(method, url, body string, headers ...string) (int, string) {
+	req, _ := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestAPIKeyControllerSuite) TestAPIKeyController_CreateAPIKey() {
+	suite.Run("error anonymous", func() {
+		code, body := suite.serve(http.MethodPost, "/api/api-keys", `{"name":"ingestion","scopes":["posts:write"]}`)
+		suite.Equal(`{"result":"error","error":"authentication required"}`, body)
+		suite.Equal(http.StatusUnauthorized, code)
+	})
+
+	suite.Run("error from validation", func() {
+		code, body := suite.serve(http.MethodPost, "/api/api-keys", `{"name":"ingestion","scopes":["posts:delete"]}`,
+			"Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"result":"errors","error":[{"field":"Scopes[0]","message":"Should be one of posts:read posts:write tags:admin"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("success", func() {
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockAPIKeyService.EXPECT().CreateAPIKey(gomock.Any(), dto.CreateAPIKeyRequest{Name: "ingestion", Scopes: []string{"posts:write"}}).
+			Return(&dto.CreateAPIKeyResponse{
+				GetAPIKeyResponse: dto.GetAPIKeyResponse{ID: 1, Name: "ingestion", Prefix: "afk_abcdefgh", Scopes: []string{"posts:write"}, CreatedAt: createdAt},
+				Key:               "afk_abcdefghijkl",
+			}, nil)
+
+		code, body := suite.serve(http.MethodPost, "/api/api-keys", `{"name":"ingestion","scopes":["posts:write"]}`,
+			"Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"data":{"id":1,"name":"ingestion","prefix":"afk_abcdefgh","scopes":["posts:write"],"created_at":"2024-05-01T10:00:00Z","key":"afk_abcdefghijkl"},"result":"ok"}`, body)
+		suite.Equal(http.StatusCreated, code)
+	})
+}
+
+func (suite *TestAPIKeyControllerSuite) TestAPIKeyController_GetAPIKeys() {
+	suite.Run("success", func() {
+		suite.MockAPIKeyService.EXPECT().GetAPIKeys(gomock.Any(), dto.GetAPIKeysRequest{}).
+			Return([]dto.GetAPIKeyResponse{}, &dto.Pagination{Page: 1, PerPage: 20}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/api-keys", "", "Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"data":[],"pagination":{"page":1,"per_page":20,"total":0},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestAPIKeyControllerSuite) TestAPIKeyController_RevokeAPIKey() {
+	suite.Run("error not found", func() {
+		suite.MockAPIKeyService.EXPECT().RevokeAPIKey(gomock.Any(), 2).Return(dto.ErrorNotFound{EntityName: "api key", EntityID: 2})
+
+		code, body := suite.serve(http.MethodDelete, "/api/api-keys/2", "", "Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"result":"error","error":"cannot find api key with id 2"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockAPIKeyService.EXPECT().RevokeAPIKey(gomock.Any(), 1).Return(nil)
+
+		code, body := suite.serve(http.MethodDelete, "/api/api-keys/1", "", "Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"result":"revoked"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestAPIKeyControllerSuite) TestAPIKeyController_Authenticate() {
+	createBody := `{"title":"test","content":"test","tags":["test"]}`
+	writeKey := auth.Principal{UserID: 7, Role: auth.RoleAuthor, APIKeyID: 1, Scopes: []string{auth.ScopePostsWrite}}
+
+	suite.Run("error invalid key", func() {
+		suite.MockAPIKeyService.EXPECT().AuthenticateAPIKey(gomock.Any(), "afk_revoked").Return(auth.Principal{}, dto.ErrorUnauthorized{Message: "invalid api key"})
+
+		code, body := suite.serve(http.MethodPost, "/api/posts", createBody, "X-API-Key", "afk_revoked")
+		suite.Equal(`{"result":"error","error":"invalid api key"}`, body)
+		suite.Equal(http.StatusUnauthorized, code)
+	})
+
+	suite.Run("error token and key", func() {
+		code, body := suite.serve(http.MethodPost, "/api/posts", createBody, "X-API-Key", "afk_write", "Authorization", "Bearer "+suite.token)
+		suite.Equal(`{"result":"error","error":"use either a bearer token or an api key"}`, body)
+		suite.Equal(http.StatusUnauthorized, code)
+	})
+
+	suite.Run("error key lacks the scope", func() {
+		suite.MockAPIKeyService.EXPECT().AuthenticateAPIKey(gomock.Any(), "afk_write").Return(writeKey, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/posts/1", "", "X-API-Key", "afk_write")
+		suite.Equal(`{"result":"error","error":"api key lacks the posts:read scope"}`, body)
+		suite.Equal(http.StatusForbidden, code)
+	})
+
+	suite.Run("error key cannot manage keys", func() {
+		suite.MockAPIKeyService.EXPECT().AuthenticateAPIKey(gomock.Any(), "afk_write").Return(writeKey, nil)
+		suite.MockAPIKeyService.EXPECT().GetAPIKeys(gomock.Any(), gomock.Any()).Return(nil, nil, dto.ErrorForbidden{Message: "api keys cannot manage api keys"})
+
+		code, body := suite.serve(http.MethodGet, "/api/api-keys", "", "X-API-Key", "afk_write")
+		suite.Equal(`{"result":"error","error":"api keys cannot manage api keys"}`, body)
+		suite.Equal(http.StatusForbidden, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockAPIKeyService.EXPECT().AuthenticateAPIKey(gomock.Any(), "afk_write").Return(writeKey, nil)
+		suite.MockPostService.EXPECT().CreatePost(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req dto.CreateOrUpdatePostRequest) error {
+			suite.Equal(7, auth.UserID(ctx.(*gin.Context).Request.Context()))
+			return nil
+		})
+
+		code, _ := suite.serve(http.MethodPost, "/api/posts", createBody, "X-API-Key", "afk_write")
+		suite.Equal(http.StatusCreated, code)
+	})
+}
@@ -0,0 +1,157 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room left in the request body for the multipart
+// boundaries and headers around the uploaded file.
+const multipartOverhead = 1 << 20
+
+type (
+	IAttachmentService interface {
+		MaxSize() int64
+		UploadAttachment(ctx context.Context, postID int, filename string, content io.Reader, size int64) (*dto.GetAttachmentResponse, error)
+		GetAttachments(ctx context.Context, postID int, req dto.GetAttachmentsRequest) ([]dto.GetAttachmentResponse, *dto.Pagination, error)
+		OpenAttachment(ctx context.Context, postID, id int) (*dto.GetAttachmentResponse, io.ReadSeekCloser, error)
+		DeleteAttachment(ctx context.Context, postID, id int) error
+	}
+
+	AttachmentController struct {
+		attachmentService IAttachmentService
+	}
+)
+
+func NewAttachmentController(attachmentService IAttachmentService) *AttachmentController {
+	return &AttachmentController{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment attaches the file of the multipart form to the post. The
+// body is cut short past the maximum size, so that oversized uploads are
+// never fully read.
+func (ac *AttachmentController) UploadAttachment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		maxSize := ac.attachmentService.MaxSize()
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+		req := dto.UploadAttachmentRequest{}
+		err = c.ShouldBind(&req)
+		if err != nil {
+			var errMaxBytes *http.MaxBytesError
+			if errors.As(err, &errMaxBytes) {
+				err = dto.ErrorPayloadTooLarge{Message: "attachment is too large"}
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.NewBaseResponse(nil, err))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		file, err := req.File.Open()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			return
+		}
+		defer file.Close()
+
+		attachment, err := ac.attachmentService.UploadAttachment(c, uri.ID, req.File.Filename, file, req.File.Size)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, dto.NewBaseResponse(attachment, nil))
+	}
+}
+
+func (ac *AttachmentController) GetAttachments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.GetAttachmentsRequest{}
+		err = c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		attachments, pagination, err := ac.attachmentService.GetAttachments(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(attachments, *pagination))
+	}
+}
+
+// DownloadAttachment serves the content of the attachment, answering the
+// Range and the conditional headers against its checksum. The content is
+// always downloaded rather than displayed, so that uploads cannot run as
+// pages of the api.
+func (ac *AttachmentController) DownloadAttachment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriAttachmentRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		attachment, content, err := ac.attachmentService.OpenAttachment(c, uri.ID, uri.AttachmentID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+		defer content.Close()
+
+		c.Header("Content-Type", attachment.ContentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("ETag", `"`+attachment.Checksum+`"`)
+		http.ServeContent(c.Writer, c.Request, "", attachment.CreatedAt, content)
+	}
+}
+
+func (ac *AttachmentController) DeleteAttachment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriAttachmentRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = ac.attachmentService.DeleteAttachment(c, uri.ID, uri.AttachmentID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("deleted", nil))
+	}
+}
@@ -0,0 +1,230 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	AttachmentController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestAttachmentControllerSuite struct {
+	suite.Suite
+
+	Ctrl                  *gomock.Controller
+	MockAttachmentService *AttachmentController.MockIAttachmentService
+	router                *gin.Engine
+}
+
+func (suite *TestAttachmentControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockAttachmentService = AttachmentController.NewMockIAttachmentService(suite.Ctrl)
+	suite.MockAttachmentService.EXPECT().MaxSize().Return(int64(1024)).AnyTimes()
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, controller.NewPostController(AttachmentController.NewMockIPostService(suite.Ctrl)))
+	routes.AttachmentRoute(apiGroup, apiGroup, controller.NewAttachmentController(suite.MockAttachmentService))
+}
+
+func (suite *TestAttachmentControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestAttachmentControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAttachmentControllerSuite))
+}
+
+func (suite *TestAttachmentControllerSuite) upload(field, filename, content string) (int, string) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, _ := form.CreateFormFile(field, filename)
+	part.Write([]byte(content))
+	form.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/attachments", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestAttachmentControllerSuite) download(header http.Header) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/attachments/3", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+// readSeekNopCloser is the content of an attachment opened by the service.
+type readSeekNopCloser struct {
+	io.ReadSeeker
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
+func (suite *TestAttachmentControllerSuite) TestAttachmentController_UploadAttachment() {
+	suite.Run("error without file", func() {
+		code, body := suite.upload("other", "a.txt", "hello world")
+		suite.Equal(`{"result":"errors","error":[{"field":"File","message":"This field is required"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error body too large", func() {
+		code, body := suite.upload("file", "a.txt", strings.Repeat("a", 1024+2<<20))
+		suite.Equal(`{"result":"error","error":"attachment is too large"}`, body)
+		suite.Equal(http.StatusRequestEntityTooLarge, code)
+	})
+
+	suite.Run("error unsupported type", func() {
+		suite.MockAttachmentService.EXPECT().UploadAttachment(gomock.Any(), 1, "page.html", gomock.Any(), int64(6)).
+			Return(nil, dto.ErrorUnsupportedMediaType{Message: "attachments of type text/html are not allowed"})
+
+		code, body := suite.upload("file", "page.html", "<html>")
+		suite.Equal(`{"result":"error","error":"attachments of type text/html are not allowed"}`, body)
+		suite.Equal(http.StatusUnsupportedMediaType, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockAttachmentService.EXPECT().UploadAttachment(gomock.Any(), 1, "a.txt", gomock.Any(), int64(11)).
+			DoAndReturn(func(ctx context.Context, postID int, filename string, content io.Reader, size int64) (*dto.GetAttachmentResponse, error) {
+				body, _ := io.ReadAll(content)
+				suite.Equal("hello world", string(body))
+				return &dto.GetAttachmentResponse{
+					ID:          3,
+					Filename:    "a.txt",
+					ContentType: "text/plain",
+					Size:        11,
+					Checksum:    "sum",
+					CreatedAt:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				}, nil
+			})
+
+		code, body := suite.upload("file", "a.txt", "hello world")
+		suite.Equal(`{"data":{"id":3,"filename":"a.txt","content_type":"text/plain","size":11,"checksum":"sum","created_at":"2024-05-01T10:00:00Z"},"result":"ok"}`, body)
+		suite.Equal(http.StatusCreated, code)
+	})
+}
+
+func (suite *TestAttachmentControllerSuite) TestAttachmentController_GetAttachments() {
+	suite.Run("success", func() {
+		suite.MockAttachmentService.EXPECT().GetAttachments(gomock.Any(), 1, dto.GetAttachmentsRequest{Limit: 1}).
+			Return([]dto.GetAttachmentResponse{{ID: 3, Filename: "a.txt", ContentType: "text/plain", Size: 11, Checksum: "sum",
+				CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}},
+				&dto.Pagination{Page: 1, PerPage: 1, Total: 1}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/attachments?limit=1", nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(`{"data":[{"id":3,"filename":"a.txt","content_type":"text/plain","size":11,"checksum":"sum","created_at":"2024-05-01T10:00:00Z"}],"pagination":{"page":1,"per_page":1,"total":1},"result":"ok"}`, w.Body.String())
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestAttachmentControllerSuite) TestAttachmentController_DownloadAttachment() {
+	attachment := &dto.GetAttachmentResponse{
+		ID:          3,
+		Filename:    "hello world.txt",
+		ContentType: "text/plain",
+		Size:        11,
+		Checksum:    "sum",
+		CreatedAt:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	open := func() {
+		suite.MockAttachmentService.EXPECT().OpenAttachment(gomock.Any(), 1, 3).
+			Return(attachment, readSeekNopCloser{strings.NewReader("hello world")}, nil)
+	}
+
+	suite.Run("error not found", func() {
+		suite.MockAttachmentService.EXPECT().OpenAttachment(gomock.Any(), 1, 3).
+			Return(nil, nil, dto.ErrorNotFound{EntityName: "attachment", EntityID: 3})
+
+		w := suite.download(nil)
+		suite.Equal(`{"result":"error","error":"cannot find attachment with id 3"}`, w.Body.String())
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		open()
+
+		w := suite.download(nil)
+		suite.Equal("hello world", w.Body.String())
+		suite.Equal(http.StatusOK, w.Code)
+		suite.Equal("text/plain", w.Header().Get("Content-Type"))
+		suite.Equal(`attachment; filename="hello world.txt"`, w.Header().Get("Content-Disposition"))
+		suite.Equal("nosniff", w.Header().Get("X-Content-Type-Options"))
+		suite.Equal(`"sum"`, w.Header().Get("ETag"))
+		suite.Equal("bytes", w.Header().Get("Accept-Ranges"))
+	})
+
+	suite.Run("success range", func() {
+		open()
+
+		w := suite.download(http.Header{"Range": {"bytes=6-"}})
+		suite.Equal("world", w.Body.String())
+		suite.Equal(http.StatusPartialContent, w.Code)
+		suite.Equal("bytes 6-10/11", w.Header().Get("Content-Range"))
+	})
+
+	suite.Run("error range not satisfiable", func() {
+		open()
+
+		w := suite.download(http.Header{"Range": {"bytes=20-"}})
+		suite.Equal(http.StatusRequestedRangeNotSatisfiable, w.Code)
+	})
+
+	suite.Run("not modified", func() {
+		open()
+
+		w := suite.download(http.Header{"If-None-Match": {`"sum"`}})
+		suite.Empty(w.Body.String())
+		suite.Equal(http.StatusNotModified, w.Code)
+	})
+}
+
+func (suite *TestAttachmentControllerSuite) TestAttachmentController_DeleteAttachment() {
+	suite.Run("error forbidden", func() {
+		suite.MockAttachmentService.EXPECT().DeleteAttachment(gomock.Any(), 1, 3).
+			Return(dto.ErrorForbidden{Message: "the posts:edit_any permission is required"})
+
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/1/attachments/3", nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(`{"result":"error","error":"the posts:edit_any permission is required"}`, w.Body.String())
+		suite.Equal(http.StatusForbidden, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockAttachmentService.EXPECT().DeleteAttachment(gomock.Any(), 1, 3).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/1/attachments/3", nil)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(`{"result":"deleted"}`, w.Body.String())
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
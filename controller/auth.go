@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+var errAuthenticationRequired = dto.ErrorUnauthorized{Message: "authentication required"}
+
+// ITokenVerifier verifies bearer tokens, returning who they were issued to.
+type ITokenVerifier interface {
+	Verify(token string) (auth.Principal, error)
+}
+
+// Authenticate makes the request on behalf of the principal of its bearer
+// token, with the role its user has now, the services read it from the
+// context of the request. Requests without a token are anonymous, while an
+// invalid token or one of an unknown user is rejected.
+func Authenticate(verifier ITokenVerifier, userService IUserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			abortUnauthorized(c, dto.ErrorUnauthorized{Message: "authorization must be a bearer token"})
+			return
+		}
+
+		principal, err := verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			abortUnauthorized(c, dto.ErrorUnauthorized{Message: err.Error()})
+			return
+		}
+
+		user, err := userService.GetUser(c.Request.Context(), principal.UserID)
+		if err != nil {
+			status := errorStatus(err)
+			if status == http.StatusNotFound {
+				abortUnauthorized(c, dto.ErrorUnauthorized{Message: "unknown user"})
+				return
+			}
+
+			c.AbortWithStatusJSON(status, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		principal.Role = auth.Role(user.Role)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireAuth rejects the anonymous requests, it marks the routes after
+// Authenticate that are only for authenticated users.
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, ok := auth.PrincipalFrom(c.Request.Context())
+		if !ok {
+			abortUnauthorized(c, errAuthenticationRequired)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope rejects the requests made with an api key lacking the scope,
+// the other requests are not limited by scopes.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c.Request.Context())
+		if ok && !principal.HasScope(scope) {
+			err := dto.ErrorForbidden{Message: "api key lacks the " + scope + " scope"}
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireToken rejects the requests made with an api key, whatever its
+// scopes, it marks the routes reserved to the users signed in with a token.
+func RequireToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c.Request.Context())
+		if ok && principal.APIKeyID != 0 {
+			err := dto.ErrorForbidden{Message: "api keys cannot be used for this request"}
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission rejects the requests of the users whose role lacks the
+// permission. Anonymous requests are left to RequireAuth, and the permissions
+// depending on the post are checked by the services.
+func RequirePermission(permission auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c.Request.Context())
+		if ok && !principal.Can(permission) {
+			err := dto.ErrorForbidden{Message: "the " + string(permission) + " permission is required"}
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func abortUnauthorized(c *gin.Context, err dto.ErrorUnauthorized) {
+	c.Header("WWW-Authenticate", "Bearer")
+	c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewBaseResponse(nil, err))
+}
@@ -0,0 +1,170 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	PostController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestAuthSuite struct {
+	suite.Suite
+
+	Ctrl            *gomock.Controller
+	MockPostService *PostController.MockIPostService
+	MockUserService *PostController.MockIUserService
+	router          *gin.Engine
+	secret          []byte
+}
+
+func (suite *TestAuthSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockPostService = PostController.NewMockIPostService(suite.Ctrl)
+	suite.MockUserService = PostController.NewMockIUserService(suite.Ctrl)
+	suite.secret = []byte("secret")
+
+	suite.router = gin.Default()
+	suite.router.ContextWithFallback = true
+	apiGroup := suite.router.Group("/api")
+	apiGroup.Use(controller.Authenticate(auth.NewJWTVerifier(auth.WithHMACSecret(suite.secret)), suite.MockUserService))
+	authenticatedGroup := apiGroup.Group("", controller.RequireAuth())
+	routes.PostRoute(apiGroup, authenticatedGroup, controller.NewPostController(suite.MockPostService))
+	routes.UserRoute(apiGroup, authenticatedGroup, controller.NewUserController(suite.MockUserService))
+}
+
+func (suite *TestAuthSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAuthSuite))
+}
+
+func (suite *TestAuthSuite) token(subject string, expiresIn time.Duration) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+	}).SignedString(suite.secret)
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *TestAuthSuite) serve(method, url, body, authorization string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *TestAuthSuite) TestAuth() {
+	createBody := `{"title":"test","content":"test","tags":["test"]}`
+
+	suite.Run("public route is anonymous", func() {
+		suite.MockPostService.EXPECT().GetPost(gomock.Any(), 1).DoAndReturn(func(ctx context.Context, id int) (*dto.GetPostResponse, error) {
+			_, ok := auth.PrincipalFrom(ctx)
+			suite.False(ok)
+			return &dto.GetPostResponse{ID: 1}, nil
+		})
+
+		w := suite.serve(http.MethodGet, "/api/posts/1", "", "")
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("error authenticated route is anonymous", func() {
+		w := suite.serve(http.MethodPost, "/api/posts", createBody, "")
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"authentication required"}`, string(responseData))
+		suite.Equal(http.StatusUnauthorized, w.Code)
+		suite.Equal("Bearer", w.Header().Get("WWW-Authenticate"))
+	})
+
+	suite.Run("error not a bearer token", func() {
+		w := suite.serve(http.MethodGet, "/api/posts/1", "", "Basic amFuZTpzZWNyZXQ=")
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"authorization must be a bearer token"}`, string(responseData))
+		suite.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	suite.Run("error expired token on a public route", func() {
+		w := suite.serve(http.MethodGet, "/api/posts/1", "", "Bearer "+suite.token("7", -time.Hour))
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"invalid token: token has invalid claims: token is expired"}`, string(responseData))
+		suite.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	suite.Run("error unknown user", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 9).Return(nil, dto.ErrorNotFound{EntityName: "user", EntityID: 9})
+
+		w := suite.serve(http.MethodPost, "/api/posts", createBody, "Bearer "+suite.token("9", time.Hour))
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"unknown user"}`, string(responseData))
+		suite.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	suite.Run("error role lacks the permission", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 7).Return(&dto.GetUserResponse{ID: 7, Role: "viewer"}, nil)
+
+		w := suite.serve(http.MethodPost, "/api/posts", createBody, "Bearer "+suite.token("7", time.Hour))
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"the posts:create permission is required"}`, string(responseData))
+		suite.Equal(http.StatusForbidden, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 7).Return(&dto.GetUserResponse{ID: 7, Role: "author"}, nil)
+		suite.MockPostService.EXPECT().CreatePost(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req dto.CreateOrUpdatePostRequest) error {
+			principal, _ := auth.PrincipalFrom(ctx)
+			suite.Equal(auth.Principal{UserID: 7, Role: auth.RoleAuthor}, principal)
+			return nil
+		})
+
+		w := suite.serve(http.MethodPost, "/api/posts", createBody, "Bearer "+suite.token("7", time.Hour))
+		suite.Equal(http.StatusCreated, w.Code)
+	})
+}
+
+func (suite *TestAuthSuite) TestAuth_UpdateUserRole() {
+	suite.Run("error not an admin", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 7).Return(&dto.GetUserResponse{ID: 7, Role: "editor"}, nil)
+
+		w := suite.serve(http.MethodPut, "/api/users/7/role", `{"role":"admin"}`, "Bearer "+suite.token("7", time.Hour))
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"the users:manage permission is required"}`, string(responseData))
+		suite.Equal(http.StatusForbidden, w.Code)
+	})
+
+	suite.Run("error unknown role", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 1).Return(&dto.GetUserResponse{ID: 1, Role: "admin"}, nil)
+
+		w := suite.serve(http.MethodPut, "/api/users/7/role", `{"role":"owner"}`, "Bearer "+suite.token("1", time.Hour))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockUserService.EXPECT().GetUser(gomock.Any(), 1).Return(&dto.GetUserResponse{ID: 1, Role: "admin"}, nil)
+		suite.MockUserService.EXPECT().UpdateUserRole(gomock.Any(), dto.UpdateUserRoleRequest{Role: "editor"}, 7).Return(nil)
+
+		w := suite.serve(http.MethodPut, "/api/users/7/role", `{"role":"editor"}`, "Bearer "+suite.token("1", time.Hour))
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"updated"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,101 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ICommentService interface {
+		CreateComment(ctx context.Context, postID int, req dto.CreateCommentRequest) (*dto.GetCommentResponse, error)
+		GetComments(ctx context.Context, postID int, req dto.GetCommentsRequest) ([]dto.GetCommentResponse, *dto.Pagination, error)
+		DeleteComment(ctx context.Context, postID, id int) error
+	}
+
+	CommentController struct {
+		commentService ICommentService
+	}
+)
+
+func NewCommentController(commentService ICommentService) *CommentController {
+	return &CommentController{
+		commentService: commentService,
+	}
+}
+
+func (cc *CommentController) CreateComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.CreateCommentRequest{}
+		err = c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		comment, err := cc.commentService.CreateComment(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, dto.NewBaseResponse(comment, nil))
+	}
+}
+
+func (cc *CommentController) GetComments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.GetCommentsRequest{}
+		err = c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		comments, pagination, err := cc.commentService.GetComments(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(comments, *pagination))
+	}
+}
+
+func (cc *CommentController) DeleteComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriCommentRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = cc.commentService.DeleteComment(c, uri.ID, uri.CommentID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("deleted", nil))
+	}
+}
@@ -0,0 +1,134 @@
+package controller_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	CommentController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestCommentControllerSuite struct {
+	suite.Suite
+
+	Ctrl               *gomock.Controller
+	MockCommentService *CommentController.MockICommentService
+	router             *gin.Engine
+}
+
+func (suite *TestCommentControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockCommentService = CommentController.NewMockICommentService(suite.Ctrl)
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, controller.NewPostController(CommentController.NewMockIPostService(suite.Ctrl)))
+	routes.CommentRoute(apiGroup, apiGroup, controller.NewCommentController(suite.MockCommentService))
+}
+
+func (suite *TestCommentControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestCommentControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestCommentControllerSuite))
+}
+
+func (suite *TestCommentControllerSuite) serve(method, url, body string) (int, string) {
+	req, _ := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestCommentControllerSuite) TestCommentController_CreateComment() {
+	suite.Run("error without body", func() {
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/comments", `{}`)
+		suite.Equal(`{"result":"errors","error":[{"field":"Body","message":"This field is required"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error draft", func() {
+		suite.MockCommentService.EXPECT().CreateComment(gomock.Any(), 1, dto.CreateCommentRequest{Body: "nice"}).
+			Return(nil, dto.ErrorConflict{Message: "only published posts can be commented"})
+
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/comments", `{"body":"nice"}`)
+		suite.Equal(`{"result":"error","error":"only published posts can be commented"}`, body)
+		suite.Equal(http.StatusConflict, code)
+	})
+
+	suite.Run("success", func() {
+		parentID := 2
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockCommentService.EXPECT().CreateComment(gomock.Any(), 1, dto.CreateCommentRequest{Body: "nice", ParentID: &parentID}).
+			Return(&dto.GetCommentResponse{
+				ID:        3,
+				ParentID:  &parentID,
+				Author:    &dto.Author{ID: 7, Name: "jane"},
+				Body:      "nice",
+				CreatedAt: createdAt,
+				Replies:   []dto.GetCommentResponse{},
+			}, nil)
+
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/comments", `{"body":"nice","parent_id":2}`)
+		suite.Equal(`{"data":{"id":3,"parent_id":2,"author":{"id":7,"name":"jane"},"body":"nice","created_at":"2024-05-01T10:00:00Z","replies":[]},"result":"ok"}`, body)
+		suite.Equal(http.StatusCreated, code)
+	})
+}
+
+func (suite *TestCommentControllerSuite) TestCommentController_GetComments() {
+	suite.Run("error from service", func() {
+		suite.MockCommentService.EXPECT().GetComments(gomock.Any(), 1, gomock.Any()).Return(nil, nil, errors.New("test error from service"))
+
+		code, body := suite.serve(http.MethodGet, "/api/posts/1/comments", "")
+		suite.Equal(`{"result":"error","error":"test error from service"}`, body)
+		suite.Equal(http.StatusInternalServerError, code)
+	})
+
+	suite.Run("success", func() {
+		one := 1
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockCommentService.EXPECT().GetComments(gomock.Any(), 1, dto.GetCommentsRequest{Limit: 1}).
+			Return([]dto.GetCommentResponse{
+				{ID: 1, Body: "first", CreatedAt: createdAt, Replies: []dto.GetCommentResponse{
+					{ID: 2, ParentID: &one, Body: "reply", CreatedAt: createdAt, Replies: []dto.GetCommentResponse{}},
+				}},
+			}, &dto.Pagination{Page: 1, PerPage: 1, Total: 2}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/posts/1/comments?limit=1", "")
+		suite.Equal(`{"data":[{"id":1,"body":"first","created_at":"2024-05-01T10:00:00Z","replies":[{"id":2,"parent_id":1,"body":"reply","created_at":"2024-05-01T10:00:00Z","replies":[]}]}],"pagination":{"page":1,"per_page":1,"total":2,"next":"/api/posts/1/comments?limit=1\u0026offset=1"},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestCommentControllerSuite) TestCommentController_DeleteComment() {
+	suite.Run("error not found", func() {
+		suite.MockCommentService.EXPECT().DeleteComment(gomock.Any(), 1, 3).Return(dto.ErrorNotFound{EntityName: "comment", EntityID: 3})
+
+		code, body := suite.serve(http.MethodDelete, "/api/posts/1/comments/3", "")
+		suite.Equal(`{"result":"error","error":"cannot find comment with id 3"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockCommentService.EXPECT().DeleteComment(gomock.Any(), 1, 3).Return(nil)
+
+		code, body := suite.serve(http.MethodDelete, "/api/posts/1/comments/3", "")
+		suite.Equal(`{"result":"deleted"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+)
+
+// errorStatus maps the typed errors returned by the services to their http
+// status code. Any other error is treated as an internal server error.
+func errorStatus(err error) int {
+	var (
+		errNotFound   dto.ErrorNotFound
+		errBadRequest dto.ErrorBadRequest
+		errConflict   dto.ErrorConflict
+
+		errUnauthorized dto.ErrorUnauthorized
+		errForbidden    dto.ErrorForbidden
+
+		errPreconditionFailed   dto.ErrorPreconditionFailed
+		errPreconditionRequired dto.ErrorPreconditionRequired
+
+		errPayloadTooLarge      dto.ErrorPayloadTooLarge
+		errUnsupportedMediaType dto.ErrorUnsupportedMediaType
+	)
+
+	switch {
+	case errors.As(err, &errNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &errBadRequest):
+		return http.StatusBadRequest
+	case errors.As(err, &errConflict):
+		return http.StatusConflict
+	case errors.As(err, &errUnauthorized):
+		return http.StatusUnauthorized
+	case errors.As(err, &errForbidden):
+		return http.StatusForbidden
+	case errors.As(err, &errPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.As(err, &errPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.As(err, &errPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &errUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
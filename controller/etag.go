@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errIfMatchRequired = dto.ErrorPreconditionRequired{Message: "If-Match header is required"}
+	errIfMatchAny      = dto.ErrorPreconditionRequired{Message: "If-Match header must hold the ETag of the post, not *"}
+	errIfMatchFailed   = dto.ErrorPreconditionFailed{Message: "post has been modified"}
+)
+
+// postETag is the entity tag of a post, its version followed by its update
+// time. The update time also changes along with what is shown with the post
+// without changing its version, such as its reactions.
+func postETag(version int, updatedAt time.Time) string {
+	return fmt.Sprintf(`"%d-%d"`, version, updatedAt.UnixNano())
+}
+
+// ifMatchVersion parses the If-Match header into the version of the post the
+// client expects to change. The header is either an entity tag of the post or
+// its bare version, only the version is compared. It is required so that
+// clients cannot overwrite changes they have not seen, which * would allow.
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, errIfMatchRequired
+	}
+
+	if header == "*" {
+		return 0, errIfMatchAny
+	}
+
+	// weak tags never match, as If-Match uses the strong comparison
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, errIfMatchFailed
+	}
+
+	tag, _, _ := strings.Cut(header[1:len(header)-1], "-")
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, errIfMatchFailed
+	}
+
+	return version, nil
+}
+
+// postsETag is the weak entity tag of a listing of posts, changing along with
+// its summary. The listings of authenticated requests depend on who made them,
+// as the drafts are only listed to some users, so their user and role are
+// part of the tag.
+func postsETag(summary dto.PostsSummary, principal auth.Principal) string {
+	if principal.UserID == 0 {
+		return fmt.Sprintf(`W/"%d-%d"`, summary.Total, summary.LastModified.UnixNano())
+	}
+
+	return fmt.Sprintf(`W/"%d-%d-%d-%s"`, summary.Total, summary.LastModified.UnixNano(), principal.UserID, principal.Role)
+}
+
+// notModified reports whether the conditional headers of a GET request match
+// the current representation. As in RFC 9110, If-None-Match takes precedence
+// over If-Modified-Since and uses the weak comparison.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if header := req.Header.Get("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if header := req.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+
+		// Last-Modified has a precision of a second
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
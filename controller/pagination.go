@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// setPaginationLinks fills the next and prev links of pagination based on
+// the current request url, keeping every other query param untouched.
+// Cursor paginated requests only get a next link since cursors can't seek
+// backward.
+func setPaginationLinks(c *gin.Context, offset int, pagination *dto.Pagination) {
+	if c.Query("cursor") != "" {
+		if pagination.NextCursor != "" {
+			pagination.Next = cursorLink(c, pagination.PerPage, pagination.NextCursor)
+		}
+		return
+	}
+
+	if int64(offset+pagination.PerPage) < pagination.Total {
+		pagination.Next = pageLink(c, pagination.PerPage, offset+pagination.PerPage)
+	}
+
+	if offset > 0 {
+		pagination.Prev = pageLink(c, pagination.PerPage, max(offset-pagination.PerPage, 0))
+	}
+}
+
+func pageLink(c *gin.Context, limit, offset int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	u.RawQuery = query.Encode()
+
+	return u.RequestURI()
+}
+
+func cursorLink(c *gin.Context, limit int, cursor string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+
+	return u.RequestURI()
+}
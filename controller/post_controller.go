@@ -4,42 +4,114 @@ package controller
 
 import (
 	"context"
-	"errors"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/dto"
 	"github.com/gin-gonic/gin"
 )
 
 type (
 	IPostService interface {
-		GetPosts(ctx context.Context) ([]dto.GetPostResponse, error)
+		GetPosts(ctx context.Context, req dto.GetPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error)
+		GetPostsSummary(ctx context.Context, req dto.GetPostsRequest) (*dto.PostsSummary, error)
+		SearchPosts(ctx context.Context, req dto.SearchPostsRequest) ([]dto.SearchPostResponse, *dto.Pagination, error)
 		CreatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest) error
 		GetPost(ctx context.Context, ids int) (*dto.GetPostResponse, error)
-		UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id int) error
-		DeletePost(ctx context.Context, id int) error
+		GetPostBySlug(ctx context.Context, slug string) (*dto.GetPostResponse, error)
+		UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id, version int) error
+		PatchPost(ctx context.Context, req dto.PatchPostRequest, id, version int) error
+		UpdatePostStatus(ctx context.Context, status string, id, version int) error
+		DeletePost(ctx context.Context, id, version int) error
+		GetTrashedPosts(ctx context.Context, req dto.GetTrashedPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error)
+		RestorePost(ctx context.Context, id int) error
+		PurgePost(ctx context.Context, id int) error
+		GetPostRevisions(ctx context.Context, id int, req dto.GetPostRevisionsRequest) ([]dto.GetPostRevisionResponse, *dto.Pagination, error)
+		GetPostRevision(ctx context.Context, id, rev int) (*dto.GetPostRevisionResponse, error)
+		DiffPostRevisions(ctx context.Context, id int, req dto.DiffPostRevisionsRequest) (*dto.PostRevisionDiffResponse, error)
+		RestorePostRevision(ctx context.Context, id, rev, version int) error
 	}
 
 	PostController struct {
-		postService IPostService
+		postService  IPostService
+		cacheControl string
 	}
+
+	PostControllerOption func(*PostController)
 )
 
-func NewPostController(postService IPostService) *PostController {
-	return &PostController{
-		postService: postService,
+// WithCacheControl sets the Cache-Control header of the post reads. By default
+// clients have to revalidate their cached posts on every use.
+func WithCacheControl(cacheControl string) PostControllerOption {
+	return func(pc *PostController) {
+		pc.cacheControl = cacheControl
+	}
+}
+
+func NewPostController(postService IPostService, opts ...PostControllerOption) *PostController {
+	pc := &PostController{
+		postService:  postService,
+		cacheControl: "no-cache",
 	}
+
+	for _, opt := range opts {
+		opt(pc)
+	}
+
+	return pc
 }
 
 func (pc *PostController) GetPosts() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		posts, err := pc.postService.GetPosts(c)
+		req := dto.GetPostsRequest{}
+		err := c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		summary, err := pc.postService.GetPostsSummary(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		principal, _ := auth.PrincipalFrom(c.Request.Context())
+		if pc.checkNotModified(c, postsETag(*summary, principal), summary.LastModified) {
+			return
+		}
+
+		posts, pagination, err := pc.postService.GetPosts(c, req)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
 			return
 		}
 
-		c.JSON(http.StatusOK, dto.NewBaseResponse(posts, nil))
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(posts, *pagination))
+	}
+}
+
+func (pc *PostController) SearchPosts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.SearchPostsRequest{}
+		err := c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		posts, pagination, err := pc.postService.SearchPosts(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(posts, *pagination))
 	}
 }
 
@@ -54,7 +126,7 @@ func (pc *PostController) CreatePost() gin.HandlerFunc {
 
 		err = pc.postService.CreatePost(c, req)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
 			return
 		}
 
@@ -71,6 +143,12 @@ func (pc *PostController) UpdatePost() gin.HandlerFunc {
 			return
 		}
 
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
 		req := dto.CreateOrUpdatePostRequest{}
 		err = c.ShouldBindJSON(&req)
 		if err != nil {
@@ -78,14 +156,9 @@ func (pc *PostController) UpdatePost() gin.HandlerFunc {
 			return
 		}
 
-		err = pc.postService.UpdatePost(c, req, uri.ID)
+		err = pc.postService.UpdatePost(c, req, uri.ID, version)
 		if err != nil {
-			var errNotFound dto.ErrorNotFound
-			if errors.As(err, &errNotFound) {
-				c.AbortWithStatusJSON(http.StatusNotFound, dto.NewBaseResponse(nil, err))
-				return
-			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
 			return
 		}
 
@@ -93,6 +166,65 @@ func (pc *PostController) UpdatePost() gin.HandlerFunc {
 	}
 }
 
+func (pc *PostController) PatchPost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.PatchPostRequest{}
+		err = c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.PatchPost(c, req, uri.ID, version)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("updated", nil))
+	}
+}
+
+// UpdatePostStatus moves the post to the status, the transitions being
+// checked by the service.
+func (pc *PostController) UpdatePostStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.UpdatePostStatus(c, status, uri.ID, version)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(status, nil))
+	}
+}
+
 func (pc *PostController) DeletePost() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		uri := dto.UriPostRequest{}
@@ -102,14 +234,15 @@ func (pc *PostController) DeletePost() gin.HandlerFunc {
 			return
 		}
 
-		err = pc.postService.DeletePost(c, uri.ID)
+		version, err := ifMatchVersion(c)
 		if err != nil {
-			var errNotFound dto.ErrorNotFound
-			if errors.As(err, &errNotFound) {
-				c.AbortWithStatusJSON(http.StatusNotFound, dto.NewBaseResponse(nil, err))
-				return
-			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.DeletePost(c, uri.ID, version)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
 			return
 		}
 
@@ -117,6 +250,161 @@ func (pc *PostController) DeletePost() gin.HandlerFunc {
 	}
 }
 
+func (pc *PostController) GetTrashedPosts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.GetTrashedPostsRequest{}
+		err := c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		posts, pagination, err := pc.postService.GetTrashedPosts(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(posts, *pagination))
+	}
+}
+
+func (pc *PostController) RestorePost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.RestorePost(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("restored", nil))
+	}
+}
+
+func (pc *PostController) PurgePost() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.PurgePost(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("purged", nil))
+	}
+}
+
+func (pc *PostController) GetPostRevisions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.GetPostRevisionsRequest{}
+		err = c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		revisions, pagination, err := pc.postService.GetPostRevisions(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(revisions, *pagination))
+	}
+}
+
+func (pc *PostController) GetPostRevision() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRevisionRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		revision, err := pc.postService.GetPostRevision(c, uri.ID, uri.Revision)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(revision, nil))
+	}
+}
+
+func (pc *PostController) DiffPostRevisions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.DiffPostRevisionsRequest{}
+		err = c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		diff, err := pc.postService.DiffPostRevisions(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(diff, nil))
+	}
+}
+
+func (pc *PostController) RestorePostRevision() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostRevisionRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = pc.postService.RestorePostRevision(c, uri.ID, uri.Revision, version)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("restored", nil))
+	}
+}
+
 func (pc *PostController) GetPost() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		uri := dto.UriPostRequest{}
@@ -128,15 +416,72 @@ func (pc *PostController) GetPost() gin.HandlerFunc {
 
 		post, err := pc.postService.GetPost(c, uri.ID)
 		if err != nil {
-			var errNotFound dto.ErrorNotFound
-			if errors.As(err, &errNotFound) {
-				c.AbortWithStatusJSON(http.StatusNotFound, dto.NewBaseResponse(nil, err))
-				return
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		if pc.checkNotModified(c, postETag(post.Version, post.UpdatedAt), post.UpdatedAt) {
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(post, nil))
+	}
+}
+
+// GetPostBySlug gets the post with the slug. The old slugs of a post redirect
+// permanently to its current slug.
+func (pc *PostController) GetPostBySlug() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriPostSlugRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		post, err := pc.postService.GetPostBySlug(c, uri.Slug)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		if post.Slug != uri.Slug {
+			location := url.URL{
+				Path:     strings.TrimSuffix(c.Request.URL.Path, uri.Slug) + post.Slug,
+				RawQuery: c.Request.URL.RawQuery,
 			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewBaseResponse(nil, err))
+			c.Redirect(http.StatusMovedPermanently, location.String())
+			return
+		}
+
+		if pc.checkNotModified(c, postETag(post.Version, post.UpdatedAt), post.UpdatedAt) {
 			return
 		}
 
 		c.JSON(http.StatusOK, dto.NewBaseResponse(post, nil))
 	}
 }
+
+// checkNotModified sets the cache headers of a read, and answers it with 304
+// Not Modified when the client already has the current representation. The
+// reads depend on who makes them, so the authenticated ones are private.
+func (pc *PostController) checkNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	cacheControl := pc.cacheControl
+	if _, ok := auth.PrincipalFrom(c.Request.Context()); ok && !strings.Contains(cacheControl, "private") {
+		cacheControl = "private, " + cacheControl
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Vary", "Authorization, X-API-Key")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if !notModified(c.Request, etag, lastModified) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
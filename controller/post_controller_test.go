@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
 	"github.com/elangreza14/assetfindr-test/controller"
 	"github.com/elangreza14/assetfindr-test/dto"
@@ -43,7 +46,7 @@ func (suite *TestPostControllerSuite) TestPostController_CreatePost() {
 
 	router := gin.Default()
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	routes.PostRoute(apiGroup, apiGroup, postController)
 
 	suite.Run("error from validation", func() {
 		errRequestBody := dto.CreateOrUpdatePostRequest{
@@ -106,10 +109,147 @@ func (suite *TestPostControllerSuite) TestPostController_GetPosts() {
 
 	router := gin.Default()
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error from query", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?limit=1000&offset=-1", nil)
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"errors","error":[{"field":"Limit","message":"Should be less than or equal to 100"},{"field":"Offset","message":"Should be greater than or equal to 0"}]}`, string(responseData))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error from match query", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?tag=go&match=none", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"errors","error":[{"field":"Match","message":"Should be one of any all"}]}`, string(responseData))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success with tags", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 3}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), dto.GetPostsRequest{Tags: []string{"go", "sql"}, Match: "any"}).
+			Return([]dto.GetPostResponse{}, &dto.Pagination{Page: 1, PerPage: 10}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?tag=go&tag=sql&match=any", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[],"pagination":{"page":1,"per_page":10,"total":0},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("success with fields", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 1}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), dto.GetPostsRequest{Fields: "title,tags", Include: "author"}).
+			Return([]dto.GetPostResponse{{
+				ID:            1,
+				Title:         "test",
+				ContentFormat: "plain",
+				Tags:          []string{"go"},
+				Author:        &dto.Author{ID: 7, Name: "jane"},
+				Fields:        []string{"id", "title", "tags", "author"},
+			}}, &dto.Pagination{Page: 1, PerPage: 10, Total: 1}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?fields=title,tags&include=author", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[{"id":1,"title":"test","tags":["go"],"author":{"id":7,"name":"jane"}}],"pagination":{"page":1,"per_page":10,"total":1},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("error from summary", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(nil, errors.New("test error from service"))
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"test error from service"}`, string(responseData))
+		suite.Equal(http.StatusInternalServerError, w.Code)
+	})
+
+	lastModified := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+	summary := &dto.PostsSummary{Total: 3, LastModified: lastModified}
+	etag := fmt.Sprintf(`W/"3-%d"`, lastModified.UnixNano())
+
+	suite.Run("not modified with if none match", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), dto.GetPostsRequest{Limit: 1}).Return(summary, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?limit=1", nil)
+		req.Header.Set("If-None-Match", `"other", `+etag)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal("", w.Body.String())
+		suite.Equal(etag, w.Header().Get("ETag"))
+		suite.Equal(http.StatusNotModified, w.Code)
+	})
+
+	suite.Run("not modified with if modified since", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(summary, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts", nil)
+		req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 10:00:00 GMT")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusNotModified, w.Code)
+	})
+
+	suite.Run("modified", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(summary, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]dto.GetPostResponse{}, &dto.Pagination{Page: 1, PerPage: 10, Total: 3}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts", nil)
+		req.Header.Set("If-None-Match", `W/"2-1"`)
+		req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 10:00:00 GMT")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(etag, w.Header().Get("ETag"))
+		suite.Equal("Wed, 01 May 2024 10:00:00 GMT", w.Header().Get("Last-Modified"))
+		suite.Equal("no-cache", w.Header().Get("Cache-Control"))
+		suite.Equal("Authorization, X-API-Key", w.Header().Get("Vary"))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("modified for another user", func() {
+		authenticatedRouter := gin.Default()
+		authenticatedGroup := authenticatedRouter.Group("/api", func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Principal{UserID: 7, Role: auth.RoleAuthor}))
+		})
+		routes.PostRoute(authenticatedGroup, authenticatedGroup, postController)
+
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(summary, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]dto.GetPostResponse{}, &dto.Pagination{Page: 1, PerPage: 10, Total: 3}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?status=draft", nil)
+		req.Header.Set("If-None-Match", etag)
+
+		w := httptest.NewRecorder()
+		authenticatedRouter.ServeHTTP(w, req)
+
+		suite.Equal(fmt.Sprintf(`W/"3-%d-7-author"`, lastModified.UnixNano()), w.Header().Get("ETag"))
+		suite.Equal("private, no-cache", w.Header().Get("Cache-Control"))
+		suite.Equal("Authorization, X-API-Key", w.Header().Get("Vary"))
+		suite.Equal(http.StatusOK, w.Code)
+	})
 
 	suite.Run("error from service", func() {
-		suite.MockPostService.EXPECT().GetPosts(gomock.Any()).Return(nil, errors.New("test error from service"))
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 3}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("test error from service"))
 		req, _ := http.NewRequest(http.MethodGet, "/api/posts", nil)
 		req.Header.Set("Content-Type", "application/json")
 
@@ -122,20 +262,72 @@ func (suite *TestPostControllerSuite) TestPostController_GetPosts() {
 	})
 
 	suite.Run("success", func() {
-		suite.MockPostService.EXPECT().GetPosts(gomock.Any()).Return([]dto.GetPostResponse{{
-			ID:      1,
-			Title:   "test",
-			Content: "test",
-			Tags:    []string{"test"},
-		}}, nil)
-		req, _ := http.NewRequest(http.MethodGet, "/api/posts", nil)
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 3}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), dto.GetPostsRequest{Limit: 1, Offset: 1}).Return([]dto.GetPostResponse{{
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []string{"test"},
+			Status:        "published",
+		}}, &dto.Pagination{Page: 2, PerPage: 1, Total: 3}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?limit=1&offset=1", nil)
 		req.Header.Set("Content-Type", "application/json")
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
 		responseData, _ := io.ReadAll(w.Body)
-		suite.Equal(`{"data":[{"id":1,"title":"test","content":"test","tags":["test"]}],"result":"ok"}`, string(responseData))
+		suite.Equal(`{"data":[{"id":1,"title":"test","content":"test","content_format":"plain","content_html":"\u003cp\u003etest\u003c/p\u003e\n","excerpt":"test","word_count":1,"reading_time":1,"tags":["test"],"status":"published","version":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"pagination":{"page":2,"per_page":1,"total":3,"next":"/api/posts?limit=1\u0026offset=2","prev":"/api/posts?limit=1\u0026offset=0"},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_GetPostsWithCursor() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error invalid cursor", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 3}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), dto.GetPostsRequest{Cursor: "abc"}).Return(nil, nil, dto.ErrorBadRequest{Message: "invalid cursor"})
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?cursor=abc", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"invalid cursor"}`, string(responseData))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(&dto.PostsSummary{Total: 3}, nil)
+		suite.MockPostService.EXPECT().GetPosts(gomock.Any(), dto.GetPostsRequest{Limit: 1, Cursor: "abc"}).Return([]dto.GetPostResponse{{
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []string{"test"},
+			Status:        "published",
+		}}, &dto.Pagination{PerPage: 1, Total: 3, NextCursor: "def"}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts?limit=1&cursor=abc", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[{"id":1,"title":"test","content":"test","content_format":"plain","content_html":"\u003cp\u003etest\u003c/p\u003e\n","excerpt":"test","word_count":1,"reading_time":1,"tags":["test"],"status":"published","version":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"pagination":{"per_page":1,"total":3,"next":"/api/posts?cursor=def\u0026limit=1","next_cursor":"def"},"result":"ok"}`, string(responseData))
 		suite.Equal(http.StatusOK, w.Code)
 	})
 }
@@ -153,7 +345,7 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 
 	router := gin.Default()
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	routes.PostRoute(apiGroup, apiGroup, postController)
 
 	suite.Run("error from uri id", func() {
 		errRequestBody := dto.CreateOrUpdatePostRequest{
@@ -166,6 +358,7 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 		bodyReader := bytes.NewReader(errPayload)
 		req, _ := http.NewRequest(http.MethodPut, "/api/posts/1212aasas", bodyReader)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -186,6 +379,7 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 		bodyReader := bytes.NewReader(errPayload)
 		req, _ := http.NewRequest(http.MethodPut, "/api/posts/1212", bodyReader)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -204,9 +398,10 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 
 	suite.Run("error internal from service", func() {
 		bodyReader := bytes.NewReader(payload)
-		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 1).Return(errors.New("test error from service"))
+		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 1, 1).Return(errors.New("test error from service"))
 		req, _ := http.NewRequest(http.MethodPut, "/api/posts/1", bodyReader)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -218,9 +413,10 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 
 	suite.Run("error not found from service", func() {
 		bodyReader := bytes.NewReader(payload)
-		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 3).Return(dto.ErrorNotFound{EntityName: "post", EntityID: 3})
+		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 3, 1).Return(dto.ErrorNotFound{EntityName: "post", EntityID: 3})
 		req, _ := http.NewRequest(http.MethodPut, "/api/posts/3", bodyReader)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -230,11 +426,64 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 		suite.Equal(http.StatusNotFound, w.Code)
 	})
 
+	suite.Run("error without if match", func() {
+		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"If-Match header is required"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionRequired, w.Code)
+	})
+
+	suite.Run("error weak if match", func() {
+		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `W/"1"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"post has been modified"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionFailed, w.Code)
+	})
+
+	suite.Run("error modified from service", func() {
+		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 2, 1).Return(dto.ErrorPreconditionFailed{Message: "post has been modified"})
+		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"post has been modified"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionFailed, w.Code)
+	})
+
+	suite.Run("error any version", func() {
+		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "*")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"If-Match header must hold the ETag of the post, not *"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionRequired, w.Code)
+	})
+
 	suite.Run("success", func() {
 		bodyReader := bytes.NewReader(payload)
-		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 2).Return(nil)
+		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 2, 1).Return(nil)
 		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bodyReader)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -243,6 +492,129 @@ func (suite *TestPostControllerSuite) TestPostController_UpdatePost() {
 		suite.Equal(`{"result":"updated"}`, string(responseData))
 		suite.Equal(http.StatusOK, w.Code)
 	})
+
+	suite.Run("success with the etag of the post", func() {
+		bodyReader := bytes.NewReader(payload)
+		suite.MockPostService.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), 2, 1).Return(nil)
+		req, _ := http.NewRequest(http.MethodPut, "/api/posts/2", bodyReader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-1714557600000000000"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_PatchPost() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	patch := func(url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPatch, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", `"4"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	suite.Run("error null field", func() {
+		w := patch("/api/posts/1", `{"title":null}`)
+
+		suite.Equal(`{"result":"error","error":"title cannot be null"}`, w.Body.String())
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error from body", func() {
+		w := patch("/api/posts/1", `{"content":"","tags":[],"add_tags":[""]}`)
+
+		suite.Equal(`{"result":"errors","error":[{"field":"Content","message":"Should be greater than 0"},{"field":"Tags","message":"Should be greater than 0"},{"field":"AddTags[0]","message":"This field is required"}]}`, w.Body.String())
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error not found from service", func() {
+		suite.MockPostService.EXPECT().PatchPost(gomock.Any(), gomock.Any(), 3, 4).Return(dto.ErrorNotFound{EntityName: "post", EntityID: 3})
+		w := patch("/api/posts/3", `{"content":"test"}`)
+
+		suite.Equal(`{"result":"error","error":"cannot find post with id 3"}`, w.Body.String())
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		title := "test"
+		suite.MockPostService.EXPECT().PatchPost(gomock.Any(), dto.PatchPostRequest{
+			Title:      &title,
+			RemoveTags: []string{"go"},
+		}, 2, 4).Return(nil)
+		w := patch("/api/posts/2", `{"title":"test","remove_tags":["go"]}`)
+
+		suite.Equal(`{"result":"updated"}`, w.Body.String())
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_UpdatePostStatus() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error without if match", func() {
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/publish", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"If-Match header is required"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionRequired, w.Code)
+	})
+
+	suite.Run("error transition not allowed", func() {
+		suite.MockPostService.EXPECT().UpdatePostStatus(gomock.Any(), "archived", 1, 2).Return(dto.ErrorConflict{Message: "cannot move post from draft to archived"})
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/archive", nil)
+		req.Header.Set("If-Match", `"2"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"cannot move post from draft to archived"}`, string(responseData))
+		suite.Equal(http.StatusConflict, w.Code)
+	})
+
+	suite.Run("success publish", func() {
+		suite.MockPostService.EXPECT().UpdatePostStatus(gomock.Any(), "published", 1, 2).Return(nil)
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/publish", nil)
+		req.Header.Set("If-Match", `"2"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"published"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("success unpublish", func() {
+		suite.MockPostService.EXPECT().UpdatePostStatus(gomock.Any(), "draft", 1, 2).Return(nil)
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/unpublish", nil)
+		req.Header.Set("If-Match", `"2"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"draft"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
 }
 
 func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
@@ -250,12 +622,13 @@ func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
 
 	router := gin.Default()
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	routes.PostRoute(apiGroup, apiGroup, postController)
 
 	suite.Run("error from uri id", func() {
 
 		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/1212aasas", nil)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -267,9 +640,10 @@ func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
 
 	suite.Run("error internal from service", func() {
 
-		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 1).Return(errors.New("test error from service"))
+		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 1, 1).Return(errors.New("test error from service"))
 		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/1", nil)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -279,11 +653,27 @@ func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
 		suite.Equal(http.StatusInternalServerError, w.Code)
 	})
 
+	suite.Run("error forbidden from service", func() {
+
+		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 2, 1).Return(dto.ErrorForbidden{Message: "only the author can change the post"})
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/2", nil)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"only the author can change the post"}`, string(responseData))
+		suite.Equal(http.StatusForbidden, w.Code)
+	})
+
 	suite.Run("error not found from service", func() {
 
-		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 3).Return(dto.ErrorNotFound{EntityName: "post", EntityID: 3})
+		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 3, 1).Return(dto.ErrorNotFound{EntityName: "post", EntityID: 3})
 		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/3", nil)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -293,11 +683,23 @@ func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
 		suite.Equal(http.StatusNotFound, w.Code)
 	})
 
+	suite.Run("error without if match", func() {
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/2", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"If-Match header is required"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionRequired, w.Code)
+	})
+
 	suite.Run("success", func() {
 
-		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 2).Return(nil)
+		suite.MockPostService.EXPECT().DeletePost(gomock.Any(), 2, 1).Return(nil)
 		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/2", nil)
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
 
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -308,12 +710,295 @@ func (suite *TestPostControllerSuite) TestPostController_DeletePost() {
 	})
 }
 
+func (suite *TestPostControllerSuite) TestPostController_GetTrashedPosts() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error from query", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/trash?limit=0a", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error internal from service", func() {
+		suite.MockPostService.EXPECT().GetTrashedPosts(gomock.Any(), dto.GetTrashedPostsRequest{}).Return(nil, nil, errors.New("test error from service"))
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/trash", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"test error from service"}`, string(responseData))
+		suite.Equal(http.StatusInternalServerError, w.Code)
+	})
+
+	suite.Run("success", func() {
+		deletedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+		suite.MockPostService.EXPECT().GetTrashedPosts(gomock.Any(), dto.GetTrashedPostsRequest{Limit: 1}).Return([]dto.GetPostResponse{{
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []string{"test"},
+			Status:        "published",
+			Version:       2,
+			CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			DeletedAt:     &deletedAt,
+		}}, &dto.Pagination{Page: 1, PerPage: 1, Total: 2}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/trash?limit=1", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[{"id":1,"title":"test","content":"test","content_format":"plain","content_html":"\u003cp\u003etest\u003c/p\u003e\n","excerpt":"test","word_count":1,"reading_time":1,"tags":["test"],"status":"published","version":2,"created_at":"2024-05-01T10:00:00Z","updated_at":"2024-05-01T10:00:00Z","deleted_at":"2024-05-02T10:00:00Z"}],"pagination":{"page":1,"per_page":1,"total":2,"next":"/api/posts/trash?limit=1\u0026offset=1"},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_RestorePost() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error not found from service", func() {
+		suite.MockPostService.EXPECT().RestorePost(gomock.Any(), 3).Return(dto.ErrorNotFound{EntityName: "deleted post", EntityID: 3})
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/3/restore", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"cannot find deleted post with id 3"}`, string(responseData))
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().RestorePost(gomock.Any(), 2).Return(nil)
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/2/restore", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"restored"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_PurgePost() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error from uri id", func() {
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/aa/purge", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error not found from service", func() {
+		suite.MockPostService.EXPECT().PurgePost(gomock.Any(), 3).Return(dto.ErrorNotFound{EntityName: "deleted post", EntityID: 3})
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/3/purge", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"cannot find deleted post with id 3"}`, string(responseData))
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().PurgePost(gomock.Any(), 2).Return(nil)
+		req, _ := http.NewRequest(http.MethodDelete, "/api/posts/2/purge", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"purged"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_GetPostRevisions() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error not found from service", func() {
+		suite.MockPostService.EXPECT().GetPostRevisions(gomock.Any(), 3, dto.GetPostRevisionsRequest{}).Return(nil, nil, dto.ErrorNotFound{EntityName: "post", EntityID: 3})
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/3/revisions", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"cannot find post with id 3"}`, string(responseData))
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().GetPostRevisions(gomock.Any(), 1, dto.GetPostRevisionsRequest{Limit: 1}).Return([]dto.GetPostRevisionResponse{{
+			Revision:      2,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			Tags:          []string{"test"},
+			CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		}}, &dto.Pagination{Page: 1, PerPage: 1, Total: 1}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/revisions?limit=1", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[{"revision":2,"title":"test","content":"test","content_format":"plain","tags":["test"],"created_at":"2024-05-01T10:00:00Z"}],"pagination":{"page":1,"per_page":1,"total":1},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_GetPostRevision() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error from uri revision", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/revisions/0", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().GetPostRevision(gomock.Any(), 1, 2).Return(&dto.GetPostRevisionResponse{
+			Revision:      2,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			Tags:          []string{"test"},
+			CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/revisions/2", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":{"revision":2,"title":"test","content":"test","content_format":"plain","tags":["test"],"created_at":"2024-05-01T10:00:00Z"},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_DiffPostRevisions() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error without from", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/revisions/diff", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().DiffPostRevisions(gomock.Any(), 1, dto.DiffPostRevisionsRequest{From: 1, To: 2}).Return(&dto.PostRevisionDiffResponse{
+			From: 1,
+			To:   2,
+			Diff: "--- revision 1\n+++ revision 2\n",
+		}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/1/revisions/diff?from=1&to=2", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":{"from":1,"to":2,"diff":"--- revision 1\n+++ revision 2\n"},"result":"ok"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_RestorePostRevision() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error without if match", func() {
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/revisions/2/restore", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"If-Match header is required"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionRequired, w.Code)
+	})
+
+	suite.Run("error post modified", func() {
+		suite.MockPostService.EXPECT().RestorePostRevision(gomock.Any(), 1, 2, 3).Return(dto.ErrorPreconditionFailed{Message: "post has been modified"})
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/revisions/2/restore", nil)
+		req.Header.Set("If-Match", `"3"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"post has been modified"}`, string(responseData))
+		suite.Equal(http.StatusPreconditionFailed, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().RestorePostRevision(gomock.Any(), 1, 2, 3).Return(nil)
+		req, _ := http.NewRequest(http.MethodPost, "/api/posts/1/revisions/2/restore", nil)
+		req.Header.Set("If-Match", `"3"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"restored"}`, string(responseData))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
 func (suite *TestPostControllerSuite) TestPostController_GetPost() {
 	postController := controller.NewPostController(suite.MockPostService)
 
 	router := gin.Default()
 	apiGroup := router.Group("/api")
-	routes.PostRoute(apiGroup, postController)
+	routes.PostRoute(apiGroup, apiGroup, postController)
 
 	suite.Run("error from uri id", func() {
 
@@ -359,10 +1044,18 @@ func (suite *TestPostControllerSuite) TestPostController_GetPost() {
 	suite.Run("success", func() {
 
 		suite.MockPostService.EXPECT().GetPost(gomock.Any(), 2).Return(&dto.GetPostResponse{
-			ID:      1,
-			Title:   "test",
-			Content: "test",
-			Tags:    []string{"test"},
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: "plain",
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []string{"test"},
+			Status:        "published",
+			Version:       5,
+			UpdatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
 		}, nil)
 		req, _ := http.NewRequest(http.MethodGet, "/api/posts/2", nil)
 		req.Header.Set("Content-Type", "application/json")
@@ -371,7 +1064,178 @@ func (suite *TestPostControllerSuite) TestPostController_GetPost() {
 		router.ServeHTTP(w, req)
 
 		responseData, _ := io.ReadAll(w.Body)
-		suite.Equal(`{"data":{"id":1,"title":"test","content":"test","tags":["test"]},"result":"ok"}`, string(responseData))
+		suite.Equal(`{"data":{"id":1,"title":"test","content":"test","content_format":"plain","content_html":"\u003cp\u003etest\u003c/p\u003e\n","excerpt":"test","word_count":1,"reading_time":1,"tags":["test"],"status":"published","version":5,"created_at":"0001-01-01T00:00:00Z","updated_at":"2024-05-01T10:00:00Z"},"result":"ok"}`, string(responseData))
+		suite.Equal(`"5-1714557600000000000"`, w.Header().Get("ETag"))
+		suite.Equal("Wed, 01 May 2024 10:00:00 GMT", w.Header().Get("Last-Modified"))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	suite.Run("not modified", func() {
+		suite.MockPostService.EXPECT().GetPost(gomock.Any(), 2).Return(&dto.GetPostResponse{ID: 2, Version: 5, UpdatedAt: updatedAt}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/2", nil)
+		req.Header.Set("If-None-Match", `W/"5-1714557600000000000"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal("", w.Body.String())
+		suite.Equal(http.StatusNotModified, w.Code)
+	})
+
+	suite.Run("modified by a reaction", func() {
+		// reactions touch the post without changing its version
+		suite.MockPostService.EXPECT().GetPost(gomock.Any(), 2).Return(&dto.GetPostResponse{
+			ID:        2,
+			Version:   5,
+			Reactions: map[string]int{"like": 1},
+			UpdatedAt: updatedAt.Add(time.Second),
+		}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/2", nil)
+		req.Header.Set("If-None-Match", `"5-1714557600000000000"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal(`"5-1714557601000000000"`, w.Header().Get("ETag"))
+		suite.Contains(w.Body.String(), `"reactions":{"like":1}`)
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("modified with custom cache control", func() {
+		router := gin.Default()
+		apiGroup := router.Group("/api")
+		routes.PostRoute(apiGroup, apiGroup, controller.NewPostController(suite.MockPostService, controller.WithCacheControl("private, max-age=60")))
+
+		suite.MockPostService.EXPECT().GetPost(gomock.Any(), 2).Return(&dto.GetPostResponse{ID: 2, Version: 6}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/2", nil)
+		req.Header.Set("If-None-Match", `"5"`)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal("private, max-age=60", w.Header().Get("Cache-Control"))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_GetPostBySlug() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error not found from service", func() {
+		suite.MockPostService.EXPECT().GetPostBySlug(gomock.Any(), "missing").Return(nil, dto.ErrorNotFound{EntityName: "post", EntitySlug: "missing"})
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/by-slug/missing", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"cannot find post with slug missing"}`, string(responseData))
+		suite.Equal(http.StatusNotFound, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().GetPostBySlug(gomock.Any(), "hello-world").Return(&dto.GetPostResponse{
+			ID:        2,
+			Title:     "hello world",
+			Slug:      "hello-world",
+			Version:   5,
+			UpdatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/by-slug/hello-world", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":{"id":2,"title":"hello world","slug":"hello-world","content":"","content_format":"","content_html":"","excerpt":"","word_count":0,"reading_time":0,"tags":null,"status":"","version":5,"created_at":"0001-01-01T00:00:00Z","updated_at":"2024-05-01T10:00:00Z"},"result":"ok"}`, string(responseData))
+		suite.Equal(`"5-1714557600000000000"`, w.Header().Get("ETag"))
+		suite.Equal(http.StatusOK, w.Code)
+	})
+
+	suite.Run("redirect from an old slug", func() {
+		suite.MockPostService.EXPECT().GetPostBySlug(gomock.Any(), "hello").Return(&dto.GetPostResponse{ID: 2, Slug: "hello-world"}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/by-slug/hello?fields=title", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		suite.Equal("/api/posts/by-slug/hello-world?fields=title", w.Header().Get("Location"))
+		suite.Equal(http.StatusMovedPermanently, w.Code)
+	})
+}
+
+func (suite *TestPostControllerSuite) TestPostController_SearchPosts() {
+	postController := controller.NewPostController(suite.MockPostService)
+
+	router := gin.Default()
+	apiGroup := router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, postController)
+
+	suite.Run("error from query", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/search", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"errors","error":[{"field":"Query","message":"This field is required"}]}`, string(responseData))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("error from service", func() {
+		suite.MockPostService.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("test error from service"))
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/search?q=golang", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"test error from service"}`, string(responseData))
+		suite.Equal(http.StatusInternalServerError, w.Code)
+	})
+
+	suite.Run("error bad request from service", func() {
+		suite.MockPostService.EXPECT().SearchPosts(gomock.Any(), gomock.Any()).Return(nil, nil, dto.ErrorBadRequest{Message: "invalid page"})
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/search?q=golang", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"result":"error","error":"invalid page"}`, string(responseData))
+		suite.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostService.EXPECT().SearchPosts(gomock.Any(), dto.SearchPostsRequest{Query: "golang"}).Return([]dto.SearchPostResponse{{
+			GetPostResponse: dto.GetPostResponse{
+				ID:            1,
+				Title:         "test",
+				Content:       "golang",
+				ContentFormat: "plain",
+				ContentHTML:   "<p>golang</p>\n",
+				Excerpt:       "golang",
+				WordCount:     1,
+				ReadingTime:   1,
+				Tags:          []string{"go"},
+				Status:        "published",
+			},
+			Rank:    0.5,
+			Snippet: "<mark>golang</mark>",
+		}}, &dto.Pagination{Page: 1, PerPage: 10, Total: 1}, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/api/posts/search?q=golang", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		suite.Equal(`{"data":[{"id":1,"title":"test","content":"golang","content_format":"plain","content_html":"\u003cp\u003egolang\u003c/p\u003e\n","excerpt":"golang","word_count":1,"reading_time":1,"tags":["go"],"status":"published","version":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","rank":0.5,"snippet":"\u003cmark\u003egolang\u003c/mark\u003e"}],"pagination":{"page":1,"per_page":10,"total":1},"result":"ok"}`, string(responseData))
 		suite.Equal(http.StatusOK, w.Code)
 	})
 }
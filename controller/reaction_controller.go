@@ -0,0 +1,66 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IReactionService interface {
+		AddReaction(ctx context.Context, postID int, kind string) error
+		RemoveReaction(ctx context.Context, postID int, kind string) error
+	}
+
+	ReactionController struct {
+		reactionService IReactionService
+	}
+)
+
+func NewReactionController(reactionService IReactionService) *ReactionController {
+	return &ReactionController{
+		reactionService: reactionService,
+	}
+}
+
+func (rc *ReactionController) AddReaction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriReactionRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = rc.reactionService.AddReaction(c, uri.ID, uri.Kind)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("added", nil))
+	}
+}
+
+func (rc *ReactionController) RemoveReaction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriReactionRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = rc.reactionService.RemoveReaction(c, uri.ID, uri.Kind)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("removed", nil))
+	}
+}
@@ -0,0 +1,96 @@
+package controller_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	ReactionController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestReactionControllerSuite struct {
+	suite.Suite
+
+	Ctrl                *gomock.Controller
+	MockReactionService *ReactionController.MockIReactionService
+	router              *gin.Engine
+}
+
+func (suite *TestReactionControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockReactionService = ReactionController.NewMockIReactionService(suite.Ctrl)
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	routes.PostRoute(apiGroup, apiGroup, controller.NewPostController(ReactionController.NewMockIPostService(suite.Ctrl)))
+	routes.ReactionRoute(apiGroup, controller.NewReactionController(suite.MockReactionService))
+}
+
+func (suite *TestReactionControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestReactionControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestReactionControllerSuite))
+}
+
+func (suite *TestReactionControllerSuite) serve(method, url string) (int, string) {
+	req, _ := http.NewRequest(method, url, bytes.NewReader(nil))
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestReactionControllerSuite) TestReactionController_AddReaction() {
+	suite.Run("error unknown kind", func() {
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/reactions/dislike")
+		suite.Equal(`{"result":"errors","error":[{"field":"Kind","message":"Should be one of like love laugh insightful"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error draft", func() {
+		suite.MockReactionService.EXPECT().AddReaction(gomock.Any(), 1, "like").
+			Return(dto.ErrorConflict{Message: "only published posts can be reacted to"})
+
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/reactions/like")
+		suite.Equal(`{"result":"error","error":"only published posts can be reacted to"}`, body)
+		suite.Equal(http.StatusConflict, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockReactionService.EXPECT().AddReaction(gomock.Any(), 1, "like").Return(nil)
+
+		code, body := suite.serve(http.MethodPost, "/api/posts/1/reactions/like")
+		suite.Equal(`{"result":"added"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestReactionControllerSuite) TestReactionController_RemoveReaction() {
+	suite.Run("error post not found", func() {
+		suite.MockReactionService.EXPECT().RemoveReaction(gomock.Any(), 1, "love").Return(dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+
+		code, body := suite.serve(http.MethodDelete, "/api/posts/1/reactions/love")
+		suite.Equal(`{"result":"error","error":"cannot find post with id 1"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockReactionService.EXPECT().RemoveReaction(gomock.Any(), 1, "love").Return(nil)
+
+		code, body := suite.serve(http.MethodDelete, "/api/posts/1/reactions/love")
+		suite.Equal(`{"result":"removed"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
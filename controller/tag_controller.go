@@ -0,0 +1,169 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	ITagService interface {
+		GetTags(ctx context.Context, req dto.GetTagsRequest) ([]dto.GetTagResponse, *dto.Pagination, error)
+		GetTag(ctx context.Context, id int) (*dto.GetTagResponse, error)
+		GetTagPosts(ctx context.Context, id int, req dto.GetTagPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error)
+		UpdateTag(ctx context.Context, req dto.UpdateTagRequest, id int) error
+		MergeTag(ctx context.Context, req dto.MergeTagRequest, id int) error
+		DeleteTag(ctx context.Context, id int) error
+	}
+
+	TagController struct {
+		tagService ITagService
+	}
+)
+
+func NewTagController(tagService ITagService) *TagController {
+	return &TagController{
+		tagService: tagService,
+	}
+}
+
+func (tc *TagController) GetTags() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.GetTagsRequest{}
+		err := c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		tags, pagination, err := tc.tagService.GetTags(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(tags, *pagination))
+	}
+}
+
+func (tc *TagController) GetTag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriTagRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		tag, err := tc.tagService.GetTag(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(tag, nil))
+	}
+}
+
+func (tc *TagController) GetTagPosts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriTagRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.GetTagPostsRequest{}
+		err = c.ShouldBindQuery(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		posts, pagination, err := tc.tagService.GetTagPosts(c, uri.ID, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		setPaginationLinks(c, req.Offset, pagination)
+		c.JSON(http.StatusOK, dto.NewPaginatedResponse(posts, *pagination))
+	}
+}
+
+func (tc *TagController) UpdateTag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriTagRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.UpdateTagRequest{}
+		err = c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = tc.tagService.UpdateTag(c, req, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("updated", nil))
+	}
+}
+
+func (tc *TagController) MergeTag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriTagRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.MergeTagRequest{}
+		err = c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = tc.tagService.MergeTag(c, req, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("merged", nil))
+	}
+}
+
+func (tc *TagController) DeleteTag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriTagRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = tc.tagService.DeleteTag(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("deleted", nil))
+	}
+}
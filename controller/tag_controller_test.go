@@ -0,0 +1,186 @@
+package controller_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	TagController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestTagControllerSuite struct {
+	suite.Suite
+
+	Ctrl           *gomock.Controller
+	MockTagService *TagController.MockITagService
+	router         *gin.Engine
+}
+
+func (suite *TestTagControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockTagService = TagController.NewMockITagService(suite.Ctrl)
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	routes.TagRoute(apiGroup, apiGroup, controller.NewTagController(suite.MockTagService))
+}
+
+func (suite *TestTagControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestTagControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestTagControllerSuite))
+}
+
+func (suite *TestTagControllerSuite) serve(method, url, body string) (int, string) {
+	req, _ := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestTagControllerSuite) TestTagController_GetTags() {
+	suite.Run("error from service", func() {
+		suite.MockTagService.EXPECT().GetTags(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("test error from service"))
+
+		code, body := suite.serve(http.MethodGet, "/api/tags", "")
+		suite.Equal(`{"result":"error","error":"test error from service"}`, body)
+		suite.Equal(http.StatusInternalServerError, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().GetTags(gomock.Any(), dto.GetTagsRequest{Limit: 1}).Return([]dto.GetTagResponse{
+			{ID: 1, Label: "go", PostCount: 2},
+		}, &dto.Pagination{Page: 1, PerPage: 1, Total: 1}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/tags?limit=1", "")
+		suite.Equal(`{"data":[{"id":1,"label":"go","post_count":2}],"pagination":{"page":1,"per_page":1,"total":1},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestTagControllerSuite) TestTagController_GetTag() {
+	suite.Run("error not found from service", func() {
+		suite.MockTagService.EXPECT().GetTag(gomock.Any(), 3).Return(nil, dto.ErrorNotFound{EntityName: "tag", EntityID: 3})
+
+		code, body := suite.serve(http.MethodGet, "/api/tags/3", "")
+		suite.Equal(`{"result":"error","error":"cannot find tag with id 3"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().GetTag(gomock.Any(), 1).Return(&dto.GetTagResponse{ID: 1, Label: "go", PostCount: 2}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/tags/1", "")
+		suite.Equal(`{"data":{"id":1,"label":"go","post_count":2},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestTagControllerSuite) TestTagController_GetTagPosts() {
+	suite.Run("error not found from service", func() {
+		suite.MockTagService.EXPECT().GetTagPosts(gomock.Any(), 3, gomock.Any()).Return(nil, nil, dto.ErrorNotFound{EntityName: "tag", EntityID: 3})
+
+		code, body := suite.serve(http.MethodGet, "/api/tags/3/posts", "")
+		suite.Equal(`{"result":"error","error":"cannot find tag with id 3"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().GetTagPosts(gomock.Any(), 1, dto.GetTagPostsRequest{}).Return([]dto.GetPostResponse{
+			{ID: 1, Title: "test", Content: "test", ContentFormat: "plain", ContentHTML: "<p>test</p>\n", Excerpt: "test", WordCount: 1, ReadingTime: 1, Tags: []string{"go"}, Status: "published"},
+		}, &dto.Pagination{Page: 1, PerPage: 10, Total: 1}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/tags/1/posts", "")
+		suite.Equal(`{"data":[{"id":1,"title":"test","content":"test","content_format":"plain","content_html":"\u003cp\u003etest\u003c/p\u003e\n","excerpt":"test","word_count":1,"reading_time":1,"tags":["go"],"status":"published","version":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"pagination":{"page":1,"per_page":10,"total":1},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestTagControllerSuite) TestTagController_UpdateTag() {
+	suite.Run("error from body", func() {
+		code, body := suite.serve(http.MethodPatch, "/api/tags/1", `{}`)
+		suite.Equal(`{"result":"errors","error":[{"field":"Label","message":"This field is required"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error conflict from service", func() {
+		suite.MockTagService.EXPECT().UpdateTag(gomock.Any(), dto.UpdateTagRequest{Label: "golang"}, 1).Return(dto.ErrorConflict{Message: "tag golang already exists, merge into it instead"})
+
+		code, body := suite.serve(http.MethodPatch, "/api/tags/1", `{"label":"golang"}`)
+		suite.Equal(`{"result":"error","error":"tag golang already exists, merge into it instead"}`, body)
+		suite.Equal(http.StatusConflict, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().UpdateTag(gomock.Any(), dto.UpdateTagRequest{Label: "golang"}, 1).Return(nil)
+
+		code, body := suite.serve(http.MethodPatch, "/api/tags/1", `{"label":"golang"}`)
+		suite.Equal(`{"result":"updated"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestTagControllerSuite) TestTagController_MergeTag() {
+	suite.Run("error from uri id", func() {
+		code, body := suite.serve(http.MethodPost, "/api/tags/abc/merge", `{"target_id":2}`)
+		suite.Equal(`{"result":"error","error":"strconv.ParseInt: parsing \"abc\": invalid syntax"}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error bad request from service", func() {
+		suite.MockTagService.EXPECT().MergeTag(gomock.Any(), dto.MergeTagRequest{TargetID: 1}, 1).Return(dto.ErrorBadRequest{Message: "cannot merge a tag into itself"})
+
+		code, body := suite.serve(http.MethodPost, "/api/tags/1/merge", `{"target_id":1}`)
+		suite.Equal(`{"result":"error","error":"cannot merge a tag into itself"}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().MergeTag(gomock.Any(), dto.MergeTagRequest{TargetID: 2}, 1).Return(nil)
+
+		code, body := suite.serve(http.MethodPost, "/api/tags/1/merge", `{"target_id":2}`)
+		suite.Equal(`{"result":"merged"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestTagControllerSuite) TestTagController_DeleteTag() {
+	suite.Run("error internal from service", func() {
+		suite.MockTagService.EXPECT().DeleteTag(gomock.Any(), 1).Return(errors.New("test error from service"))
+
+		code, body := suite.serve(http.MethodDelete, "/api/tags/1", "")
+		suite.Equal(`{"result":"error","error":"test error from service"}`, body)
+		suite.Equal(http.StatusInternalServerError, code)
+	})
+
+	suite.Run("error last tag of a post", func() {
+		suite.MockTagService.EXPECT().DeleteTag(gomock.Any(), 1).Return(dto.ErrorConflict{Message: "tag is the last tag of some posts, tag them otherwise or merge it instead"})
+
+		code, body := suite.serve(http.MethodDelete, "/api/tags/1", "")
+		suite.Equal(`{"result":"error","error":"tag is the last tag of some posts, tag them otherwise or merge it instead"}`, body)
+		suite.Equal(http.StatusConflict, code)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagService.EXPECT().DeleteTag(gomock.Any(), 1).Return(nil)
+
+		code, body := suite.serve(http.MethodDelete, "/api/tags/1", "")
+		suite.Equal(`{"result":"deleted"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
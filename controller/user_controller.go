@@ -0,0 +1,94 @@
+package controller
+
+//go:generate mockgen -source $GOFILE -destination ../mock/controller/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IUserService interface {
+		CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.GetUserResponse, error)
+		GetUser(ctx context.Context, id int) (*dto.GetUserResponse, error)
+		GetUserProfile(ctx context.Context, id int) (any, error)
+		UpdateUserRole(ctx context.Context, req dto.UpdateUserRoleRequest, id int) error
+	}
+
+	UserController struct {
+		userService IUserService
+	}
+)
+
+func NewUserController(userService IUserService) *UserController {
+	return &UserController{
+		userService: userService,
+	}
+}
+
+func (uc *UserController) CreateUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.CreateUserRequest{}
+		err := c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		user, err := uc.userService.CreateUser(c, req)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusCreated, dto.NewBaseResponse(user, nil))
+	}
+}
+
+func (uc *UserController) GetUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriUserRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		user, err := uc.userService.GetUserProfile(c, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse(user, nil))
+	}
+}
+
+func (uc *UserController) UpdateUserRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := dto.UriUserRequest{}
+		err := c.ShouldBindUri(&uri)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		req := dto.UpdateUserRoleRequest{}
+		err = c.ShouldBindJSON(&req)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		err = uc.userService.UpdateUserRole(c, req, uri.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), dto.NewBaseResponse(nil, err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewBaseResponse("updated", nil))
+	}
+}
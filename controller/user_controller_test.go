@@ -0,0 +1,140 @@
+package controller_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/cmd/http/routes"
+	"github.com/elangreza14/assetfindr-test/controller"
+	"github.com/elangreza14/assetfindr-test/dto"
+	UserController "github.com/elangreza14/assetfindr-test/mock/controller"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TestUserControllerSuite struct {
+	suite.Suite
+
+	Ctrl            *gomock.Controller
+	MockUserService *UserController.MockIUserService
+	router          *gin.Engine
+}
+
+func (suite *TestUserControllerSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockUserService = UserController.NewMockIUserService(suite.Ctrl)
+	userController := controller.NewUserController(suite.MockUserService)
+
+	suite.router = gin.Default()
+	apiGroup := suite.router.Group("/api")
+	routes.UserRoute(apiGroup, apiGroup, userController)
+}
+
+func (suite *TestUserControllerSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestUserControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestUserControllerSuite))
+}
+
+func (suite *TestUserControllerSuite) serve(method, url, body string) (int, string) {
+	req, _ := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	responseData, _ := io.ReadAll(w.Body)
+	return w.Code, string(responseData)
+}
+
+func (suite *TestUserControllerSuite) TestUserController_CreateUser() {
+	suite.Run("error from validation", func() {
+		code, body := suite.serve(http.MethodPost, "/api/users", `{"name":"jane","email":"jane"}`)
+		suite.Equal(`{"result":"errors","error":[{"field":"Email","message":"Should be an email address"}]}`, body)
+		suite.Equal(http.StatusBadRequest, code)
+	})
+
+	suite.Run("error email already used", func() {
+		suite.MockUserService.EXPECT().CreateUser(gomock.Any(), dto.CreateUserRequest{Name: "jane", Email: "jane@example.com"}).
+			Return(nil, dto.ErrorConflict{Message: "email jane@example.com is already used"})
+
+		code, body := suite.serve(http.MethodPost, "/api/users", `{"name":"jane","email":"jane@example.com"}`)
+		suite.Equal(`{"result":"error","error":"email jane@example.com is already used"}`, body)
+		suite.Equal(http.StatusConflict, code)
+	})
+
+	suite.Run("success", func() {
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockUserService.EXPECT().CreateUser(gomock.Any(), gomock.Any()).
+			Return(&dto.GetUserResponse{ID: 7, Name: "jane", Email: "jane@example.com", Role: "author", CreatedAt: createdAt}, nil)
+
+		code, body := suite.serve(http.MethodPost, "/api/users", `{"name":"jane","email":"jane@example.com"}`)
+		suite.Equal(`{"data":{"id":7,"name":"jane","email":"jane@example.com","role":"author","created_at":"2024-05-01T10:00:00Z"},"result":"ok"}`, body)
+		suite.Equal(http.StatusCreated, code)
+	})
+}
+
+func (suite *TestUserControllerSuite) TestUserController_GetUser() {
+	suite.Run("error not found", func() {
+		suite.MockUserService.EXPECT().GetUserProfile(gomock.Any(), 7).Return(nil, dto.ErrorNotFound{EntityName: "user", EntityID: 7})
+
+		code, body := suite.serve(http.MethodGet, "/api/users/7", "")
+		suite.Equal(`{"result":"error","error":"cannot find user with id 7"}`, body)
+		suite.Equal(http.StatusNotFound, code)
+	})
+
+	suite.Run("success public profile", func() {
+		suite.MockUserService.EXPECT().GetUserProfile(gomock.Any(), 7).Return(dto.Author{ID: 7, Name: "jane"}, nil)
+
+		code, body := suite.serve(http.MethodGet, "/api/users/7", "")
+		suite.Equal(`{"data":{"id":7,"name":"jane"},"result":"ok"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
+
+func (suite *TestUserControllerSuite) TestUserController_UpdateUserRole() {
+	serveAs := func(principal auth.Principal) (int, string) {
+		router := gin.Default()
+		apiGroup := router.Group("/api", func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		})
+		routes.UserRoute(apiGroup, apiGroup, controller.NewUserController(suite.MockUserService))
+
+		req, _ := http.NewRequest(http.MethodPut, "/api/users/7/role", bytes.NewReader([]byte(`{"role":"editor"}`)))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		responseData, _ := io.ReadAll(w.Body)
+		return w.Code, string(responseData)
+	}
+
+	suite.Run("error api key of an admin", func() {
+		code, body := serveAs(auth.Principal{UserID: 1, Role: auth.RoleAdmin, APIKeyID: 3, Scopes: []string{auth.ScopePostsRead}})
+		suite.Equal(`{"result":"error","error":"api keys cannot be used for this request"}`, body)
+		suite.Equal(http.StatusForbidden, code)
+	})
+
+	suite.Run("error editor", func() {
+		code, body := serveAs(auth.Principal{UserID: 2, Role: auth.RoleEditor})
+		suite.Equal(`{"result":"error","error":"the users:manage permission is required"}`, body)
+		suite.Equal(http.StatusForbidden, code)
+	})
+
+	suite.Run("success admin", func() {
+		suite.MockUserService.EXPECT().UpdateUserRole(gomock.Any(), dto.UpdateUserRoleRequest{Role: "editor"}, 7).Return(nil)
+
+		code, body := serveAs(auth.Principal{UserID: 1, Role: auth.RoleAdmin})
+		suite.Equal(`{"result":"updated"}`, body)
+		suite.Equal(http.StatusOK, code)
+	})
+}
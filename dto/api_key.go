@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,gt=0,dive,oneof=posts:read posts:write tags:admin"`
+}
+
+type GetAPIKeysRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type UriAPIKeyRequest struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+type GetAPIKeyResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the key, the key itself is never shown again
+	// after its creation.
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only response holding the key.
+type CreateAPIKeyResponse struct {
+	GetAPIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type UploadAttachmentRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"`
+}
+
+type GetAttachmentsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type UriAttachmentRequest struct {
+	ID           int `uri:"id" binding:"required,gt=0"`
+	AttachmentID int `uri:"attachment_id" binding:"required,gt=0"`
+}
+
+type GetAttachmentResponse struct {
+	ID       int    `json:"id"`
+	Filename string `json:"filename"`
+	// ContentType is sniffed from the content, the type claimed by the
+	// client is ignored.
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -7,9 +7,19 @@ import (
 )
 
 type SuccessResponse struct {
-	Data   any    `json:"data,omitempty"`
-	Result string `json:"result"`
-	Err    any    `json:"error,omitempty"`
+	Data       any         `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Result     string      `json:"result"`
+	Err        any         `json:"error,omitempty"`
+}
+
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	Total      int64  `json:"total"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type SuccessResponsePlain struct {
@@ -57,6 +67,14 @@ func NewBaseResponse(data any, err error) any {
 	}
 }
 
+func NewPaginatedResponse(data any, pagination Pagination) any {
+	return SuccessResponse{
+		Data:       data,
+		Pagination: &pagination,
+		Result:     "ok",
+	}
+}
+
 type ErrorField struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
@@ -68,6 +86,16 @@ func getErrorMsg(fe validator.FieldError) string {
 		return "This field is required"
 	case "gt":
 		return "Should be greater than " + fe.Param()
+	case "gte":
+		return "Should be greater than or equal to " + fe.Param()
+	case "lte":
+		return "Should be less than or equal to " + fe.Param()
+	case "oneof":
+		return "Should be one of " + fe.Param()
+	case "email":
+		return "Should be an email address"
+	case "max":
+		return "Should be at most " + fe.Param() + " characters long"
 	}
 	return "Unknown error"
 }
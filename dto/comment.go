@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+	// ParentID replies to a comment of the same post, the comment starts a
+	// thread when it is not set.
+	ParentID *int `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+type GetCommentsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type UriCommentRequest struct {
+	ID        int `uri:"id" binding:"required,gt=0"`
+	CommentID int `uri:"comment_id" binding:"required,gt=0"`
+}
+
+type GetCommentResponse struct {
+	ID        int       `json:"id"`
+	ParentID  *int      `json:"parent_id,omitempty"`
+	Author    *Author   `json:"author,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	// Replies are the replies to the comment, the oldest first, each with
+	// their own replies.
+	Replies []GetCommentResponse `json:"replies"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type ErrorBadRequest struct {
+	Message string
+}
+
+func (e ErrorBadRequest) Error() string {
+	return e.Message
+}
@@ -0,0 +1,9 @@
+package dto
+
+type ErrorConflict struct {
+	Message string
+}
+
+func (e ErrorConflict) Error() string {
+	return e.Message
+}
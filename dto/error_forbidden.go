@@ -0,0 +1,9 @@
+package dto
+
+type ErrorForbidden struct {
+	Message string
+}
+
+func (e ErrorForbidden) Error() string {
+	return e.Message
+}
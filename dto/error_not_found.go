@@ -5,8 +5,14 @@ import "fmt"
 type ErrorNotFound struct {
 	EntityName string
 	EntityID   int
+	// EntitySlug identifies the entity instead of EntityID when it is set.
+	EntitySlug string
 }
 
 func (e ErrorNotFound) Error() string {
+	if e.EntitySlug != "" {
+		return fmt.Sprintf("cannot find %s with slug %s", e.EntityName, e.EntitySlug)
+	}
+
 	return fmt.Sprintf("cannot find %s with id %d", e.EntityName, e.EntityID)
 }
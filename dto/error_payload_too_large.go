@@ -0,0 +1,9 @@
+package dto
+
+type ErrorPayloadTooLarge struct {
+	Message string
+}
+
+func (e ErrorPayloadTooLarge) Error() string {
+	return e.Message
+}
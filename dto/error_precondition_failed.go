@@ -0,0 +1,9 @@
+package dto
+
+type ErrorPreconditionFailed struct {
+	Message string
+}
+
+func (e ErrorPreconditionFailed) Error() string {
+	return e.Message
+}
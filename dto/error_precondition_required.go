@@ -0,0 +1,9 @@
+package dto
+
+type ErrorPreconditionRequired struct {
+	Message string
+}
+
+func (e ErrorPreconditionRequired) Error() string {
+	return e.Message
+}
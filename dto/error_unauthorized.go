@@ -0,0 +1,9 @@
+package dto
+
+type ErrorUnauthorized struct {
+	Message string
+}
+
+func (e ErrorUnauthorized) Error() string {
+	return e.Message
+}
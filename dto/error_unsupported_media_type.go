@@ -0,0 +1,9 @@
+package dto
+
+type ErrorUnsupportedMediaType struct {
+	Message string
+}
+
+func (e ErrorUnsupportedMediaType) Error() string {
+	return e.Message
+}
@@ -1,20 +1,210 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
 // implement this https://blog.logrocket.com/gin-binding-in-go-a-tutorial-with-examples/
 
 type CreateOrUpdatePostRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Tags    []string `json:"tags" binding:"required,gt=0,dive,required"`
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	// ContentFormat is the markup of Content, plain when a post is created
+	// without it and unchanged when a post is updated without it.
+	ContentFormat string   `json:"content_format" binding:"omitempty,oneof=plain markdown"`
+	Tags          []string `json:"tags" binding:"required,gt=0,dive,required"`
+	// PublishAt schedules a draft to be published automatically, it has to
+	// be in the future. Updating a post without it cancels its schedule.
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// PatchPostRequest is a JSON merge patch (RFC 7396) of a post. Only the
+// fields present are updated, and since none of them can be removed, null is
+// rejected. AddTags and RemoveTags are applied after Tags.
+type PatchPostRequest struct {
+	Title         *string   `json:"title" binding:"omitnil,gt=0"`
+	Content       *string   `json:"content" binding:"omitnil,gt=0"`
+	ContentFormat *string   `json:"content_format" binding:"omitnil,oneof=plain markdown"`
+	Tags          *[]string `json:"tags" binding:"omitnil,gt=0,dive,required"`
+	AddTags       []string  `json:"add_tags" binding:"omitempty,dive,required"`
+	RemoveTags    []string  `json:"remove_tags" binding:"omitempty,dive,required"`
+}
+
+func (r *PatchPostRequest) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"title", "content", "content_format", "tags"} {
+		if value, ok := fields[name]; ok && bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			return ErrorBadRequest{Message: name + " cannot be null"}
+		}
+	}
+
+	type patchPostRequest PatchPostRequest
+	return json.Unmarshal(data, (*patchPostRequest)(r))
 }
 
 type UriPostRequest struct {
 	ID int `uri:"id" binding:"required,gt=0"`
 }
 
+type UriPostSlugRequest struct {
+	Slug string `uri:"slug" binding:"required"`
+}
+
 type GetPostResponse struct {
-	ID      int      `json:"id"`
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// Slug is not set for the posts created before slugs existed, until they
+	// are updated.
+	Slug          string `json:"slug,omitempty"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	// ContentHTML is the content rendered in its format, sanitized so that it
+	// can be embedded in a page as is.
+	ContentHTML string `json:"content_html"`
+	// Excerpt is the beginning of the text of the content, and ReadingTime
+	// the minutes it takes to read it.
+	Excerpt     string   `json:"excerpt"`
+	WordCount   int      `json:"word_count"`
+	ReadingTime int      `json:"reading_time"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`
+	// Author is not set for the posts written before users existed.
+	Author *Author `json:"author,omitempty"`
+	// Reactions counts the reactions to the post by kind, it is only set
+	// once the post has reactions.
+	Reactions map[string]int `json:"reactions,omitempty"`
+	// PublishedAt is only set once the post has been published.
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	// PublishAt is only set for the drafts scheduled to be published.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// DeletedAt is only set for the posts of the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Comments are the threads of the post, only set when they are included
+	// and the post has comments.
+	Comments []GetCommentResponse `json:"comments,omitempty"`
+	// Fields are the only fields marshalled, all of them are when it is nil.
+	Fields []string `json:"-"`
+}
+
+func (r GetPostResponse) MarshalJSON() ([]byte, error) {
+	type getPostResponse GetPostResponse
+	data, err := json.Marshal(getPostResponse(r))
+	if err != nil || r.Fields == nil {
+		return data, err
+	}
+
+	return selectFields(data, r.Fields)
+}
+
+type GetTrashedPostsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+// PostsSummary is what changes whenever a post of a listing changes, used to
+// validate cached listings.
+type PostsSummary struct {
+	Total        int64
+	LastModified time.Time
+}
+
+type GetPostsRequest struct {
+	Limit  int      `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int      `form:"offset" binding:"omitempty,gte=0"`
+	Cursor string   `form:"cursor"`
+	Tags   []string `form:"tag" binding:"omitempty,dive,required"`
+	Match  string   `form:"match" binding:"omitempty,oneof=any all"`
+	// Status lists the posts in the status, published by default.
+	Status string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	// Author lists the posts of the user with the id.
+	Author int `form:"author" binding:"omitempty,gt=0"`
+	// Fields is the comma separated list of the fields of the posts wanted,
+	// all of them when it is empty.
+	Fields string `form:"fields"`
+	// Include is the comma separated list of the resources embedded in the
+	// posts, author and comments. The author is embedded when neither Fields
+	// nor Include is set.
+	Include string `form:"include"`
+}
+
+type SearchPostsRequest struct {
+	Query  string `form:"q" binding:"required"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int    `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type SearchPostResponse struct {
+	GetPostResponse
+	Rank float64 `json:"rank"`
+	// Snippet is a fragment of the content with matches wrapped in <mark>.
+	Snippet string `json:"snippet"`
+}
+
+// MarshalJSON marshals the post along with its rank and snippet, which the
+// MarshalJSON of the post alone would leave out.
+func (r SearchPostResponse) MarshalJSON() ([]byte, error) {
+	post, err := json.Marshal(r.GetPostResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := json.Marshal(struct {
+		Rank    float64 `json:"rank"`
+		Snippet string  `json:"snippet"`
+	}{r.Rank, r.Snippet})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(post[:len(post)-1], ','), match[1:]...), nil
+}
+
+// selectFields only keeps the fields of the JSON object, in their order.
+func selectFields(data []byte, fields []string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	_, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		value := json.RawMessage{}
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(fields, key.(string)) {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
 }
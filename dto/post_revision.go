@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type UriPostRevisionRequest struct {
+	ID       int `uri:"id" binding:"required,gt=0"`
+	Revision int `uri:"rev" binding:"required,gt=0"`
+}
+
+type GetPostRevisionsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+// DiffPostRevisionsRequest compares revision From to revision To, or to the
+// current version of the post when To is not set.
+type DiffPostRevisionsRequest struct {
+	From int `form:"from" binding:"required,gt=0"`
+	To   int `form:"to" binding:"omitempty,gt=0"`
+}
+
+type GetPostRevisionResponse struct {
+	Revision      int       `json:"revision"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Tags          []string  `json:"tags"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type PostRevisionDiffResponse struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	// Diff is a unified diff of the title, tags and content of the revisions,
+	// empty when they are the same.
+	Diff string `json:"diff"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type UriReactionRequest struct {
+	ID   int    `uri:"id" binding:"required,gt=0"`
+	Kind string `uri:"kind" binding:"required,oneof=like love laugh insightful"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+type GetTagsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type GetTagPostsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Offset int `form:"offset" binding:"omitempty,gte=0"`
+}
+
+type UriTagRequest struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+type UpdateTagRequest struct {
+	Label string `json:"label" binding:"required"`
+}
+
+type MergeTagRequest struct {
+	TargetID int `json:"target_id" binding:"required,gt=0"`
+}
+
+type GetTagResponse struct {
+	ID        int    `json:"id"`
+	Label     string `json:"label"`
+	PostCount int64  `json:"post_count"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CreateUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+type UriUserRequest struct {
+	ID int `uri:"id" binding:"required,gt=0"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer author editor admin"`
+}
+
+type GetUserResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Author is the public profile of the user who wrote a post.
+type Author struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
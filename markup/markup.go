@@ -0,0 +1,103 @@
+// Package markup renders the content of the posts to HTML that is safe to
+// embed in a page, whatever the users wrote.
+package markup
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+var (
+	// markdown renders GitHub flavored markdown. The raw HTML of the content
+	// is kept, as the sanitizer strips what is not allowed from it.
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	policy = newPolicy()
+
+	blankLines = regexp.MustCompile(`\n[ \t]*\n\s*`)
+)
+
+// newPolicy allows the elements and attributes of user generated content,
+// links are never followed and any script, style or event handler is
+// removed.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	// task lists of GitHub flavored markdown
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Markdown renders the markdown content to sanitized HTML.
+func Markdown(content string) (string, error) {
+	buf := bytes.Buffer{}
+	err := markdown.Convert([]byte(content), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	return Sanitize(buf.String()), nil
+}
+
+// Plain renders the plain text content to HTML, each run of lines separated
+// by a blank line becoming a paragraph.
+func Plain(content string) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+	if content == "" {
+		return ""
+	}
+
+	paragraphs := blankLines.Split(content, -1)
+	for i, paragraph := range paragraphs {
+		paragraphs[i] = "<p>" + strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n") + "</p>"
+	}
+
+	return strings.Join(paragraphs, "\n") + "\n"
+}
+
+// Highlight renders the plain text to HTML, the parts of it between start and
+// stop being marked. The text is escaped, so that the highlights are the only
+// elements, and a highlight left open is closed at the end of the text.
+func Highlight(text, start, stop string) string {
+	buf := strings.Builder{}
+	marked := false
+	for {
+		delimiter, tag := start, "<mark>"
+		if marked {
+			delimiter, tag = stop, "</mark>"
+		}
+
+		before, after, found := strings.Cut(text, delimiter)
+		buf.WriteString(html.EscapeString(before))
+		if !found {
+			break
+		}
+
+		buf.WriteString(tag)
+		marked = !marked
+		text = after
+	}
+
+	if marked {
+		buf.WriteString("</mark>")
+	}
+
+	return buf.String()
+}
+
+// Sanitize strips the elements and attributes of the HTML that are not
+// allowed, keeping their text.
+func Sanitize(s string) string {
+	return policy.Sanitize(s)
+}
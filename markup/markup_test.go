@@ -0,0 +1,123 @@
+package markup_test
+
+import (
+	"testing"
+
+	. "github.com/elangreza14/assetfindr-test/markup"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestMarkupSuite struct {
+	suite.Suite
+}
+
+func TestMarkupTestSuite(t *testing.T) {
+	suite.Run(t, new(TestMarkupSuite))
+}
+
+func (suite *TestMarkupSuite) TestMarkup_Markdown() {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"emphasis", "hello *world*", "<p>hello <em>world</em></p>\n"},
+		{"link", "[go](https://go.dev)", `<p><a href="https://go.dev" rel="nofollow">go</a></p>` + "\n"},
+		{"code block", "```go\nfmt.Println(1)\n```", `<pre><code class="language-go">fmt.Println(1)` + "\n</code></pre>\n"},
+		{"strikethrough", "~~old~~", "<p><del>old</del></p>\n"},
+		{"task list", "- [x] done", `<ul>` + "\n" + `<li><input checked="" disabled="" type="checkbox"> done</li>` + "\n</ul>\n"},
+		{"script", "hi <script>alert(1)</script>", "<p>hi </p>\n"},
+		{"event handler", `<img src="cat.png" onerror="alert(1)">`, `<img src="cat.png">`},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"iframe", `<iframe src="https://evil.test"></iframe>`, ""},
+		{"style", `<p style="color:red">red</p>`, "<p>red</p>"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			res, err := Markdown(tc.content)
+			suite.NoError(err)
+			suite.Equal(tc.expected, res)
+		})
+	}
+}
+
+func (suite *TestMarkupSuite) TestMarkup_Plain() {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"blank", " \n ", ""},
+		{"escaped", "<b>1 & 2</b>", "<p>&lt;b&gt;1 &amp; 2&lt;/b&gt;</p>\n"},
+		{"paragraphs", "first\r\nline\n\n \nsecond", "<p>first<br>\nline</p>\n<p>second</p>\n"},
+		{"markdown is not rendered", "*hi*", "<p>*hi*</p>\n"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, Plain(tc.content))
+		})
+	}
+}
+
+func (suite *TestMarkupSuite) TestMarkup_Highlight() {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"marked", "a [match] b", "a <mark>match</mark> b"},
+		{"escaped", `<script>alert(1)</script> [x] <img src=x onerror="y">`, "&lt;script&gt;alert(1)&lt;/script&gt; <mark>x</mark> &lt;img src=x onerror=&#34;y&#34;&gt;"},
+		{"mark of the text is escaped", "<mark>[x]</mark>", "&lt;mark&gt;<mark>x</mark>&lt;/mark&gt;"},
+		{"unbalanced", "[x", "<mark>x</mark>"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, Highlight(tc.text, "[", "]"))
+		})
+	}
+}
+
+func (suite *TestMarkupSuite) TestMarkup_Text() {
+	testCases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"paragraphs", "<p>first<br>\nline</p>\n<p>second</p>\n", "first line second"},
+		{"entities", "<p>1 &amp; 2 &lt;3</p>", "1 & 2 <3"},
+		{"cells", "<table><tr><td>a</td><td>b</td></tr></table>", "a b"},
+		{"inline", "<p>hello <em>world</em>!</p>", "hello world !"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, Text(tc.html))
+		})
+	}
+}
+
+func (suite *TestMarkupSuite) TestMarkup_Excerpt() {
+	testCases := []struct {
+		name     string
+		text     string
+		max      int
+		expected string
+	}{
+		{"short", "hello world", 11, "hello world"},
+		{"cut at a word", "hello world again", 13, "hello world…"},
+		{"cut at a space", "hello world again", 11, "hello world…"},
+		{"punctuation", "hello, world", 8, "hello…"},
+		{"long word", "helloworld", 5, "hello…"},
+		{"runes", "héllo wörld", 8, "héllo…"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, Excerpt(tc.text, tc.max))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package markup
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Text extracts the text of the HTML, the elements being separated by spaces
+// and the runs of whitespace collapsed.
+func Text(s string) string {
+	buf := strings.Builder{}
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(buf.String()), " ")
+		case html.TextToken:
+			buf.Write(tokenizer.Text())
+		default:
+			buf.WriteByte(' ')
+		}
+	}
+}
+
+// Excerpt cuts the text to at most max runes, at the end of a word unless the
+// first word is longer than max, with an ellipsis when it is cut.
+func Excerpt(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	cut := max
+	for cut > 0 && !unicode.IsSpace(runes[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		cut = max
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api_key_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source api_key_controller.go -destination ../mock/controller/mock_api_key_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/elangreza14/assetfindr-test/auth"
+	dto "github.com/elangreza14/assetfindr-test/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAPIKeyService is a mock of IAPIKeyService interface.
+type MockIAPIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyServiceMockRecorder
+}
+
+// MockIAPIKeyServiceMockRecorder is the mock recorder for MockIAPIKeyService.
+type MockIAPIKeyServiceMockRecorder struct {
+	mock *MockIAPIKeyService
+}
+
+// NewMockIAPIKeyService creates a new mock instance.
+func NewMockIAPIKeyService(ctrl *gomock.Controller) *MockIAPIKeyService {
+	mock := &MockIAPIKeyService{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyService) EXPECT() *MockIAPIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockIAPIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, key)
+	ret0, _ := ret[0].(auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) AuthenticateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).AuthenticateAPIKey), ctx, key)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIAPIKeyService) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, req)
+	ret0, _ := ret[0].(*dto.CreateAPIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) CreateAPIKey(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).CreateAPIKey), ctx, req)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockIAPIKeyService) GetAPIKeys(ctx context.Context, req dto.GetAPIKeysRequest) ([]dto.GetAPIKeyResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, req)
+	ret0, _ := ret[0].([]dto.GetAPIKeyResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockIAPIKeyServiceMockRecorder) GetAPIKeys(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockIAPIKeyService)(nil).GetAPIKeys), ctx, req)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIAPIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) RevokeAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).RevokeAPIKey), ctx, id)
+}
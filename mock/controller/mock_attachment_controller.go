@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attachment_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source attachment_controller.go -destination ../mock/controller/mock_attachment_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	dto "github.com/elangreza14/assetfindr-test/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAttachmentService is a mock of IAttachmentService interface.
+type MockIAttachmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAttachmentServiceMockRecorder
+}
+
+// MockIAttachmentServiceMockRecorder is the mock recorder for MockIAttachmentService.
+type MockIAttachmentServiceMockRecorder struct {
+	mock *MockIAttachmentService
+}
+
+// NewMockIAttachmentService creates a new mock instance.
+func NewMockIAttachmentService(ctrl *gomock.Controller) *MockIAttachmentService {
+	mock := &MockIAttachmentService{ctrl: ctrl}
+	mock.recorder = &MockIAttachmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAttachmentService) EXPECT() *MockIAttachmentServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockIAttachmentService) DeleteAttachment(ctx context.Context, postID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, postID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockIAttachmentServiceMockRecorder) DeleteAttachment(ctx, postID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockIAttachmentService)(nil).DeleteAttachment), ctx, postID, id)
+}
+
+// GetAttachments mocks base method.
+func (m *MockIAttachmentService) GetAttachments(ctx context.Context, postID int, req dto.GetAttachmentsRequest) ([]dto.GetAttachmentResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachments", ctx, postID, req)
+	ret0, _ := ret[0].([]dto.GetAttachmentResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAttachments indicates an expected call of GetAttachments.
+func (mr *MockIAttachmentServiceMockRecorder) GetAttachments(ctx, postID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachments", reflect.TypeOf((*MockIAttachmentService)(nil).GetAttachments), ctx, postID, req)
+}
+
+// MaxSize mocks base method.
+func (m *MockIAttachmentService) MaxSize() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxSize")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// MaxSize indicates an expected call of MaxSize.
+func (mr *MockIAttachmentServiceMockRecorder) MaxSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxSize", reflect.TypeOf((*MockIAttachmentService)(nil).MaxSize))
+}
+
+// OpenAttachment mocks base method.
+func (m *MockIAttachmentService) OpenAttachment(ctx context.Context, postID, id int) (*dto.GetAttachmentResponse, io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAttachment", ctx, postID, id)
+	ret0, _ := ret[0].(*dto.GetAttachmentResponse)
+	ret1, _ := ret[1].(io.ReadSeekCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenAttachment indicates an expected call of OpenAttachment.
+func (mr *MockIAttachmentServiceMockRecorder) OpenAttachment(ctx, postID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAttachment", reflect.TypeOf((*MockIAttachmentService)(nil).OpenAttachment), ctx, postID, id)
+}
+
+// UploadAttachment mocks base method.
+func (m *MockIAttachmentService) UploadAttachment(ctx context.Context, postID int, filename string, content io.Reader, size int64) (*dto.GetAttachmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAttachment", ctx, postID, filename, content, size)
+	ret0, _ := ret[0].(*dto.GetAttachmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadAttachment indicates an expected call of UploadAttachment.
+func (mr *MockIAttachmentServiceMockRecorder) UploadAttachment(ctx, postID, filename, content, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAttachment", reflect.TypeOf((*MockIAttachmentService)(nil).UploadAttachment), ctx, postID, filename, content, size)
+}
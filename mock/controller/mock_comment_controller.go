@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: comment_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source comment_controller.go -destination ../mock/controller/mock_comment_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/elangreza14/assetfindr-test/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockICommentService is a mock of ICommentService interface.
+type MockICommentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockICommentServiceMockRecorder
+}
+
+// MockICommentServiceMockRecorder is the mock recorder for MockICommentService.
+type MockICommentServiceMockRecorder struct {
+	mock *MockICommentService
+}
+
+// NewMockICommentService creates a new mock instance.
+func NewMockICommentService(ctrl *gomock.Controller) *MockICommentService {
+	mock := &MockICommentService{ctrl: ctrl}
+	mock.recorder = &MockICommentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICommentService) EXPECT() *MockICommentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockICommentService) CreateComment(ctx context.Context, postID int, req dto.CreateCommentRequest) (*dto.GetCommentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, postID, req)
+	ret0, _ := ret[0].(*dto.GetCommentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockICommentServiceMockRecorder) CreateComment(ctx, postID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockICommentService)(nil).CreateComment), ctx, postID, req)
+}
+
+// DeleteComment mocks base method.
+func (m *MockICommentService) DeleteComment(ctx context.Context, postID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, postID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockICommentServiceMockRecorder) DeleteComment(ctx, postID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockICommentService)(nil).DeleteComment), ctx, postID, id)
+}
+
+// GetComments mocks base method.
+func (m *MockICommentService) GetComments(ctx context.Context, postID int, req dto.GetCommentsRequest) ([]dto.GetCommentResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", ctx, postID, req)
+	ret0, _ := ret[0].([]dto.GetCommentResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockICommentServiceMockRecorder) GetComments(ctx, postID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockICommentService)(nil).GetComments), ctx, postID, req)
+}
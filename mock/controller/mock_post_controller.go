@@ -55,17 +55,32 @@ func (mr *MockIPostServiceMockRecorder) CreatePost(ctx, req any) *gomock.Call {
 }
 
 // DeletePost mocks base method.
-func (m *MockIPostService) DeletePost(ctx context.Context, id int) error {
+func (m *MockIPostService) DeletePost(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", ctx, id)
+	ret := m.ctrl.Call(m, "DeletePost", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePost indicates an expected call of DeletePost.
-func (mr *MockIPostServiceMockRecorder) DeletePost(ctx, id any) *gomock.Call {
+func (mr *MockIPostServiceMockRecorder) DeletePost(ctx, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockIPostService)(nil).DeletePost), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockIPostService)(nil).DeletePost), ctx, id, version)
+}
+
+// DiffPostRevisions mocks base method.
+func (m *MockIPostService) DiffPostRevisions(ctx context.Context, id int, req dto.DiffPostRevisionsRequest) (*dto.PostRevisionDiffResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffPostRevisions", ctx, id, req)
+	ret0, _ := ret[0].(*dto.PostRevisionDiffResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffPostRevisions indicates an expected call of DiffPostRevisions.
+func (mr *MockIPostServiceMockRecorder) DiffPostRevisions(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffPostRevisions", reflect.TypeOf((*MockIPostService)(nil).DiffPostRevisions), ctx, id, req)
 }
 
 // GetPost mocks base method.
@@ -83,31 +98,195 @@ func (mr *MockIPostServiceMockRecorder) GetPost(ctx, ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockIPostService)(nil).GetPost), ctx, ids)
 }
 
+// GetPostBySlug mocks base method.
+func (m *MockIPostService) GetPostBySlug(ctx context.Context, slug string) (*dto.GetPostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostBySlug", ctx, slug)
+	ret0, _ := ret[0].(*dto.GetPostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostBySlug indicates an expected call of GetPostBySlug.
+func (mr *MockIPostServiceMockRecorder) GetPostBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostBySlug", reflect.TypeOf((*MockIPostService)(nil).GetPostBySlug), ctx, slug)
+}
+
+// GetPostRevision mocks base method.
+func (m *MockIPostService) GetPostRevision(ctx context.Context, id, rev int) (*dto.GetPostRevisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevision", ctx, id, rev)
+	ret0, _ := ret[0].(*dto.GetPostRevisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevision indicates an expected call of GetPostRevision.
+func (mr *MockIPostServiceMockRecorder) GetPostRevision(ctx, id, rev any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevision", reflect.TypeOf((*MockIPostService)(nil).GetPostRevision), ctx, id, rev)
+}
+
+// GetPostRevisions mocks base method.
+func (m *MockIPostService) GetPostRevisions(ctx context.Context, id int, req dto.GetPostRevisionsRequest) ([]dto.GetPostRevisionResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", ctx, id, req)
+	ret0, _ := ret[0].([]dto.GetPostRevisionResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockIPostServiceMockRecorder) GetPostRevisions(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockIPostService)(nil).GetPostRevisions), ctx, id, req)
+}
+
 // GetPosts mocks base method.
-func (m *MockIPostService) GetPosts(ctx context.Context) ([]dto.GetPostResponse, error) {
+func (m *MockIPostService) GetPosts(ctx context.Context, req dto.GetPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, req)
 	ret0, _ := ret[0].([]dto.GetPostResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockIPostServiceMockRecorder) GetPosts(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockIPostService)(nil).GetPosts), ctx, req)
+}
+
+// GetPostsSummary mocks base method.
+func (m *MockIPostService) GetPostsSummary(ctx context.Context, req dto.GetPostsRequest) (*dto.PostsSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsSummary", ctx, req)
+	ret0, _ := ret[0].(*dto.PostsSummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPosts indicates an expected call of GetPosts.
-func (mr *MockIPostServiceMockRecorder) GetPosts(ctx any) *gomock.Call {
+// GetPostsSummary indicates an expected call of GetPostsSummary.
+func (mr *MockIPostServiceMockRecorder) GetPostsSummary(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsSummary", reflect.TypeOf((*MockIPostService)(nil).GetPostsSummary), ctx, req)
+}
+
+// GetTrashedPosts mocks base method.
+func (m *MockIPostService) GetTrashedPosts(ctx context.Context, req dto.GetTrashedPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashedPosts", ctx, req)
+	ret0, _ := ret[0].([]dto.GetPostResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrashedPosts indicates an expected call of GetTrashedPosts.
+func (mr *MockIPostServiceMockRecorder) GetTrashedPosts(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockIPostService)(nil).GetPosts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashedPosts", reflect.TypeOf((*MockIPostService)(nil).GetTrashedPosts), ctx, req)
+}
+
+// PatchPost mocks base method.
+func (m *MockIPostService) PatchPost(ctx context.Context, req dto.PatchPostRequest, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPost", ctx, req, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockIPostServiceMockRecorder) PatchPost(ctx, req, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockIPostService)(nil).PatchPost), ctx, req, id, version)
+}
+
+// PurgePost mocks base method.
+func (m *MockIPostService) PurgePost(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgePost", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgePost indicates an expected call of PurgePost.
+func (mr *MockIPostServiceMockRecorder) PurgePost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgePost", reflect.TypeOf((*MockIPostService)(nil).PurgePost), ctx, id)
+}
+
+// RestorePost mocks base method.
+func (m *MockIPostService) RestorePost(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePost", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePost indicates an expected call of RestorePost.
+func (mr *MockIPostServiceMockRecorder) RestorePost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePost", reflect.TypeOf((*MockIPostService)(nil).RestorePost), ctx, id)
+}
+
+// RestorePostRevision mocks base method.
+func (m *MockIPostService) RestorePostRevision(ctx context.Context, id, rev, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePostRevision", ctx, id, rev, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePostRevision indicates an expected call of RestorePostRevision.
+func (mr *MockIPostServiceMockRecorder) RestorePostRevision(ctx, id, rev, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePostRevision", reflect.TypeOf((*MockIPostService)(nil).RestorePostRevision), ctx, id, rev, version)
+}
+
+// SearchPosts mocks base method.
+func (m *MockIPostService) SearchPosts(ctx context.Context, req dto.SearchPostsRequest) ([]dto.SearchPostResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, req)
+	ret0, _ := ret[0].([]dto.SearchPostResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockIPostServiceMockRecorder) SearchPosts(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockIPostService)(nil).SearchPosts), ctx, req)
 }
 
 // UpdatePost mocks base method.
-func (m *MockIPostService) UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id int) error {
+func (m *MockIPostService) UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePost", ctx, req, id)
+	ret := m.ctrl.Call(m, "UpdatePost", ctx, req, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdatePost indicates an expected call of UpdatePost.
-func (mr *MockIPostServiceMockRecorder) UpdatePost(ctx, req, id any) *gomock.Call {
+func (mr *MockIPostServiceMockRecorder) UpdatePost(ctx, req, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockIPostService)(nil).UpdatePost), ctx, req, id, version)
+}
+
+// UpdatePostStatus mocks base method.
+func (m *MockIPostService) UpdatePostStatus(ctx context.Context, status string, id, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostStatus", ctx, status, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePostStatus indicates an expected call of UpdatePostStatus.
+func (mr *MockIPostServiceMockRecorder) UpdatePostStatus(ctx, status, id, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockIPostService)(nil).UpdatePost), ctx, req, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostStatus", reflect.TypeOf((*MockIPostService)(nil).UpdatePostStatus), ctx, status, id, version)
 }
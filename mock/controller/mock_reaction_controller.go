@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reaction_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source reaction_controller.go -destination ../mock/controller/mock_reaction_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIReactionService is a mock of IReactionService interface.
+type MockIReactionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReactionServiceMockRecorder
+}
+
+// MockIReactionServiceMockRecorder is the mock recorder for MockIReactionService.
+type MockIReactionServiceMockRecorder struct {
+	mock *MockIReactionService
+}
+
+// NewMockIReactionService creates a new mock instance.
+func NewMockIReactionService(ctrl *gomock.Controller) *MockIReactionService {
+	mock := &MockIReactionService{ctrl: ctrl}
+	mock.recorder = &MockIReactionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReactionService) EXPECT() *MockIReactionServiceMockRecorder {
+	return m.recorder
+}
+
+// AddReaction mocks base method.
+func (m *MockIReactionService) AddReaction(ctx context.Context, postID int, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, postID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockIReactionServiceMockRecorder) AddReaction(ctx, postID, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockIReactionService)(nil).AddReaction), ctx, postID, kind)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockIReactionService) RemoveReaction(ctx context.Context, postID int, kind string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, postID, kind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockIReactionServiceMockRecorder) RemoveReaction(ctx, postID, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockIReactionService)(nil).RemoveReaction), ctx, postID, kind)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tag_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source tag_controller.go -destination ../mock/controller/mock_tag_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/elangreza14/assetfindr-test/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITagService is a mock of ITagService interface.
+type MockITagService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagServiceMockRecorder
+}
+
+// MockITagServiceMockRecorder is the mock recorder for MockITagService.
+type MockITagServiceMockRecorder struct {
+	mock *MockITagService
+}
+
+// NewMockITagService creates a new mock instance.
+func NewMockITagService(ctrl *gomock.Controller) *MockITagService {
+	mock := &MockITagService{ctrl: ctrl}
+	mock.recorder = &MockITagServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagService) EXPECT() *MockITagServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteTag mocks base method.
+func (m *MockITagService) DeleteTag(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockITagServiceMockRecorder) DeleteTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockITagService)(nil).DeleteTag), ctx, id)
+}
+
+// GetTag mocks base method.
+func (m *MockITagService) GetTag(ctx context.Context, id int) (*dto.GetTagResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(*dto.GetTagResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockITagServiceMockRecorder) GetTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockITagService)(nil).GetTag), ctx, id)
+}
+
+// GetTagPosts mocks base method.
+func (m *MockITagService) GetTagPosts(ctx context.Context, id int, req dto.GetTagPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagPosts", ctx, id, req)
+	ret0, _ := ret[0].([]dto.GetPostResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTagPosts indicates an expected call of GetTagPosts.
+func (mr *MockITagServiceMockRecorder) GetTagPosts(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagPosts", reflect.TypeOf((*MockITagService)(nil).GetTagPosts), ctx, id, req)
+}
+
+// GetTags mocks base method.
+func (m *MockITagService) GetTags(ctx context.Context, req dto.GetTagsRequest) ([]dto.GetTagResponse, *dto.Pagination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, req)
+	ret0, _ := ret[0].([]dto.GetTagResponse)
+	ret1, _ := ret[1].(*dto.Pagination)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockITagServiceMockRecorder) GetTags(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockITagService)(nil).GetTags), ctx, req)
+}
+
+// MergeTag mocks base method.
+func (m *MockITagService) MergeTag(ctx context.Context, req dto.MergeTagRequest, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTag", ctx, req, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeTag indicates an expected call of MergeTag.
+func (mr *MockITagServiceMockRecorder) MergeTag(ctx, req, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTag", reflect.TypeOf((*MockITagService)(nil).MergeTag), ctx, req, id)
+}
+
+// UpdateTag mocks base method.
+func (m *MockITagService) UpdateTag(ctx context.Context, req dto.UpdateTagRequest, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, req, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockITagServiceMockRecorder) UpdateTag(ctx, req, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockITagService)(nil).UpdateTag), ctx, req, id)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: user_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source user_controller.go -destination ../mock/controller/mock_user_controller.go -package controller
+//
+
+// Package controller is a generated GoMock package.
+package controller
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/elangreza14/assetfindr-test/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIUserService is a mock of IUserService interface.
+type MockIUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIUserServiceMockRecorder
+}
+
+// MockIUserServiceMockRecorder is the mock recorder for MockIUserService.
+type MockIUserServiceMockRecorder struct {
+	mock *MockIUserService
+}
+
+// NewMockIUserService creates a new mock instance.
+func NewMockIUserService(ctrl *gomock.Controller) *MockIUserService {
+	mock := &MockIUserService{ctrl: ctrl}
+	mock.recorder = &MockIUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockIUserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.GetUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, req)
+	ret0, _ := ret[0].(*dto.GetUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockIUserServiceMockRecorder) CreateUser(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockIUserService)(nil).CreateUser), ctx, req)
+}
+
+// GetUser mocks base method.
+func (m *MockIUserService) GetUser(ctx context.Context, id int) (*dto.GetUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, id)
+	ret0, _ := ret[0].(*dto.GetUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockIUserServiceMockRecorder) GetUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockIUserService)(nil).GetUser), ctx, id)
+}
+
+// GetUserProfile mocks base method.
+func (m *MockIUserService) GetUserProfile(ctx context.Context, id int) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProfile", ctx, id)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProfile indicates an expected call of GetUserProfile.
+func (mr *MockIUserServiceMockRecorder) GetUserProfile(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockIUserService)(nil).GetUserProfile), ctx, id)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockIUserService) UpdateUserRole(ctx context.Context, req dto.UpdateUserRoleRequest, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", ctx, req, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockIUserServiceMockRecorder) UpdateUserRole(ctx, req, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockIUserService)(nil).UpdateUserRole), ctx, req, id)
+}
@@ -0,0 +1,221 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: attachment_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source attachment_service.go -destination ../mock/service/mock_attachment_service.go -package service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	model "github.com/elangreza14/assetfindr-test/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAttachmentRepository is a mock of IAttachmentRepository interface.
+type MockIAttachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAttachmentRepositoryMockRecorder
+}
+
+// MockIAttachmentRepositoryMockRecorder is the mock recorder for MockIAttachmentRepository.
+type MockIAttachmentRepositoryMockRecorder struct {
+	mock *MockIAttachmentRepository
+}
+
+// NewMockIAttachmentRepository creates a new mock instance.
+func NewMockIAttachmentRepository(ctrl *gomock.Controller) *MockIAttachmentRepository {
+	mock := &MockIAttachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockIAttachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAttachmentRepository) EXPECT() *MockIAttachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAttachment mocks base method.
+func (m *MockIAttachmentRepository) CreateAttachment(ctx context.Context, req model.Attachment) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, req)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockIAttachmentRepositoryMockRecorder) CreateAttachment(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockIAttachmentRepository)(nil).CreateAttachment), ctx, req)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockIAttachmentRepository) DeleteAttachment(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockIAttachmentRepositoryMockRecorder) DeleteAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockIAttachmentRepository)(nil).DeleteAttachment), ctx, id)
+}
+
+// GetAttachment mocks base method.
+func (m *MockIAttachmentRepository) GetAttachment(ctx context.Context, postID, id int) (*model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachment", ctx, postID, id)
+	ret0, _ := ret[0].(*model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachment indicates an expected call of GetAttachment.
+func (mr *MockIAttachmentRepositoryMockRecorder) GetAttachment(ctx, postID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachment", reflect.TypeOf((*MockIAttachmentRepository)(nil).GetAttachment), ctx, postID, id)
+}
+
+// GetAttachments mocks base method.
+func (m *MockIAttachmentRepository) GetAttachments(ctx context.Context, postID, limit, offset int) ([]model.Attachment, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachments", ctx, postID, limit, offset)
+	ret0, _ := ret[0].([]model.Attachment)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAttachments indicates an expected call of GetAttachments.
+func (mr *MockIAttachmentRepositoryMockRecorder) GetAttachments(ctx, postID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachments", reflect.TypeOf((*MockIAttachmentRepository)(nil).GetAttachments), ctx, postID, limit, offset)
+}
+
+// GetOrphanAttachments mocks base method.
+func (m *MockIAttachmentRepository) GetOrphanAttachments(ctx context.Context, limit int) ([]model.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanAttachments", ctx, limit)
+	ret0, _ := ret[0].([]model.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanAttachments indicates an expected call of GetOrphanAttachments.
+func (mr *MockIAttachmentRepositoryMockRecorder) GetOrphanAttachments(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanAttachments", reflect.TypeOf((*MockIAttachmentRepository)(nil).GetOrphanAttachments), ctx, limit)
+}
+
+// MockIAttachmentPostRepository is a mock of IAttachmentPostRepository interface.
+type MockIAttachmentPostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAttachmentPostRepositoryMockRecorder
+}
+
+// MockIAttachmentPostRepositoryMockRecorder is the mock recorder for MockIAttachmentPostRepository.
+type MockIAttachmentPostRepositoryMockRecorder struct {
+	mock *MockIAttachmentPostRepository
+}
+
+// NewMockIAttachmentPostRepository creates a new mock instance.
+func NewMockIAttachmentPostRepository(ctrl *gomock.Controller) *MockIAttachmentPostRepository {
+	mock := &MockIAttachmentPostRepository{ctrl: ctrl}
+	mock.recorder = &MockIAttachmentPostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAttachmentPostRepository) EXPECT() *MockIAttachmentPostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPost mocks base method.
+func (m *MockIAttachmentPostRepository) GetPost(ctx context.Context, id int) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPost", ctx, id)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPost indicates an expected call of GetPost.
+func (mr *MockIAttachmentPostRepositoryMockRecorder) GetPost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockIAttachmentPostRepository)(nil).GetPost), ctx, id)
+}
+
+// MockIBlobStore is a mock of IBlobStore interface.
+type MockIBlobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBlobStoreMockRecorder
+}
+
+// MockIBlobStoreMockRecorder is the mock recorder for MockIBlobStore.
+type MockIBlobStoreMockRecorder struct {
+	mock *MockIBlobStore
+}
+
+// NewMockIBlobStore creates a new mock instance.
+func NewMockIBlobStore(ctrl *gomock.Controller) *MockIBlobStore {
+	mock := &MockIBlobStore{ctrl: ctrl}
+	mock.recorder = &MockIBlobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBlobStore) EXPECT() *MockIBlobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIBlobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIBlobStoreMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIBlobStore)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockIBlobStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key, offset, length)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIBlobStoreMockRecorder) Get(ctx, key, offset, length any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIBlobStore)(nil).Get), ctx, key, offset, length)
+}
+
+// Put mocks base method.
+func (m *MockIBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r, size, contentType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockIBlobStoreMockRecorder) Put(ctx, key, r, size, contentType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockIBlobStore)(nil).Put), ctx, key, r, size, contentType)
+}
@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: comment_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source comment_service.go -destination ../mock/service/mock_comment_service.go -package service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/elangreza14/assetfindr-test/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockICommentRepository is a mock of ICommentRepository interface.
+type MockICommentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockICommentRepositoryMockRecorder
+}
+
+// MockICommentRepositoryMockRecorder is the mock recorder for MockICommentRepository.
+type MockICommentRepositoryMockRecorder struct {
+	mock *MockICommentRepository
+}
+
+// NewMockICommentRepository creates a new mock instance.
+func NewMockICommentRepository(ctrl *gomock.Controller) *MockICommentRepository {
+	mock := &MockICommentRepository{ctrl: ctrl}
+	mock.recorder = &MockICommentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICommentRepository) EXPECT() *MockICommentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockICommentRepository) CreateComment(ctx context.Context, req model.Comment) (*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, req)
+	ret0, _ := ret[0].(*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockICommentRepositoryMockRecorder) CreateComment(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockICommentRepository)(nil).CreateComment), ctx, req)
+}
+
+// DeleteComment mocks base method.
+func (m *MockICommentRepository) DeleteComment(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockICommentRepositoryMockRecorder) DeleteComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockICommentRepository)(nil).DeleteComment), ctx, id)
+}
+
+// GetComment mocks base method.
+func (m *MockICommentRepository) GetComment(ctx context.Context, postID, id int) (*model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, postID, id)
+	ret0, _ := ret[0].(*model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockICommentRepositoryMockRecorder) GetComment(ctx, postID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockICommentRepository)(nil).GetComment), ctx, postID, id)
+}
+
+// GetCommentReplies mocks base method.
+func (m *MockICommentRepository) GetCommentReplies(ctx context.Context, ids []int) ([]model.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentReplies", ctx, ids)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentReplies indicates an expected call of GetCommentReplies.
+func (mr *MockICommentRepositoryMockRecorder) GetCommentReplies(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentReplies", reflect.TypeOf((*MockICommentRepository)(nil).GetCommentReplies), ctx, ids)
+}
+
+// GetComments mocks base method.
+func (m *MockICommentRepository) GetComments(ctx context.Context, postID, limit, offset int) ([]model.Comment, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", ctx, postID, limit, offset)
+	ret0, _ := ret[0].([]model.Comment)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockICommentRepositoryMockRecorder) GetComments(ctx, postID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockICommentRepository)(nil).GetComments), ctx, postID, limit, offset)
+}
+
+// MockICommentPostRepository is a mock of ICommentPostRepository interface.
+type MockICommentPostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockICommentPostRepositoryMockRecorder
+}
+
+// MockICommentPostRepositoryMockRecorder is the mock recorder for MockICommentPostRepository.
+type MockICommentPostRepositoryMockRecorder struct {
+	mock *MockICommentPostRepository
+}
+
+// NewMockICommentPostRepository creates a new mock instance.
+func NewMockICommentPostRepository(ctrl *gomock.Controller) *MockICommentPostRepository {
+	mock := &MockICommentPostRepository{ctrl: ctrl}
+	mock.recorder = &MockICommentPostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICommentPostRepository) EXPECT() *MockICommentPostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPost mocks base method.
+func (m *MockICommentPostRepository) GetPost(ctx context.Context, id int) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPost", ctx, id)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPost indicates an expected call of GetPost.
+func (mr *MockICommentPostRepositoryMockRecorder) GetPost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockICommentPostRepository)(nil).GetPost), ctx, id)
+}
@@ -12,6 +12,7 @@ package service
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/elangreza14/assetfindr-test/model"
 	gomock "go.uber.org/mock/gomock"
@@ -83,19 +84,220 @@ func (mr *MockIPostRepositoryMockRecorder) GetPost(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockIPostRepository)(nil).GetPost), ctx, id)
 }
 
+// GetPostBySlug mocks base method.
+func (m *MockIPostRepository) GetPostBySlug(ctx context.Context, slug string) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostBySlug", ctx, slug)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostBySlug indicates an expected call of GetPostBySlug.
+func (mr *MockIPostRepositoryMockRecorder) GetPostBySlug(ctx, slug any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostBySlug", reflect.TypeOf((*MockIPostRepository)(nil).GetPostBySlug), ctx, slug)
+}
+
+// GetPostRevision mocks base method.
+func (m *MockIPostRepository) GetPostRevision(ctx context.Context, postID, revision int) (*model.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevision", ctx, postID, revision)
+	ret0, _ := ret[0].(*model.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevision indicates an expected call of GetPostRevision.
+func (mr *MockIPostRepositoryMockRecorder) GetPostRevision(ctx, postID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevision", reflect.TypeOf((*MockIPostRepository)(nil).GetPostRevision), ctx, postID, revision)
+}
+
+// GetPostRevisions mocks base method.
+func (m *MockIPostRepository) GetPostRevisions(ctx context.Context, postID, limit, offset int) ([]model.PostRevision, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", ctx, postID, limit, offset)
+	ret0, _ := ret[0].([]model.PostRevision)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockIPostRepositoryMockRecorder) GetPostRevisions(ctx, postID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockIPostRepository)(nil).GetPostRevisions), ctx, postID, limit, offset)
+}
+
 // GetPosts mocks base method.
-func (m *MockIPostRepository) GetPosts(ctx context.Context) ([]model.Post, error) {
+func (m *MockIPostRepository) GetPosts(ctx context.Context, filter model.PostFilter) ([]model.Post, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, filter)
 	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockIPostRepositoryMockRecorder) GetPosts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockIPostRepository)(nil).GetPosts), ctx, filter)
+}
+
+// GetPostsSummary mocks base method.
+func (m *MockIPostRepository) GetPostsSummary(ctx context.Context, filter model.PostFilter) (*model.PostsSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsSummary", ctx, filter)
+	ret0, _ := ret[0].(*model.PostsSummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPosts indicates an expected call of GetPosts.
-func (mr *MockIPostRepositoryMockRecorder) GetPosts(ctx any) *gomock.Call {
+// GetPostsSummary indicates an expected call of GetPostsSummary.
+func (mr *MockIPostRepositoryMockRecorder) GetPostsSummary(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockIPostRepository)(nil).GetPosts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsSummary", reflect.TypeOf((*MockIPostRepository)(nil).GetPostsSummary), ctx, filter)
+}
+
+// GetTrashedPost mocks base method.
+func (m *MockIPostRepository) GetTrashedPost(ctx context.Context, id int) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashedPost", ctx, id)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrashedPost indicates an expected call of GetTrashedPost.
+func (mr *MockIPostRepositoryMockRecorder) GetTrashedPost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashedPost", reflect.TypeOf((*MockIPostRepository)(nil).GetTrashedPost), ctx, id)
+}
+
+// GetTrashedPosts mocks base method.
+func (m *MockIPostRepository) GetTrashedPosts(ctx context.Context, authorID, limit, offset int) ([]model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashedPosts", ctx, authorID, limit, offset)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrashedPosts indicates an expected call of GetTrashedPosts.
+func (mr *MockIPostRepositoryMockRecorder) GetTrashedPosts(ctx, authorID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashedPosts", reflect.TypeOf((*MockIPostRepository)(nil).GetTrashedPosts), ctx, authorID, limit, offset)
+}
+
+// GetUnrenderedPosts mocks base method.
+func (m *MockIPostRepository) GetUnrenderedPosts(ctx context.Context, afterID, limit int) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnrenderedPosts", ctx, afterID, limit)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnrenderedPosts indicates an expected call of GetUnrenderedPosts.
+func (mr *MockIPostRepositoryMockRecorder) GetUnrenderedPosts(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnrenderedPosts", reflect.TypeOf((*MockIPostRepository)(nil).GetUnrenderedPosts), ctx, afterID, limit)
+}
+
+// PatchPost mocks base method.
+func (m *MockIPostRepository) PatchPost(ctx context.Context, req model.Post, columns []string, tagsToBeDeleted ...int) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, req, columns}
+	for _, a := range tagsToBeDeleted {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PatchPost", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockIPostRepositoryMockRecorder) PatchPost(ctx, req, columns any, tagsToBeDeleted ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, req, columns}, tagsToBeDeleted...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockIPostRepository)(nil).PatchPost), varargs...)
+}
+
+// PublishDuePosts mocks base method.
+func (m *MockIPostRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDuePosts", ctx, now, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDuePosts indicates an expected call of PublishDuePosts.
+func (mr *MockIPostRepositoryMockRecorder) PublishDuePosts(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDuePosts", reflect.TypeOf((*MockIPostRepository)(nil).PublishDuePosts), ctx, now, limit)
+}
+
+// PurgePost mocks base method.
+func (m *MockIPostRepository) PurgePost(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgePost", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgePost indicates an expected call of PurgePost.
+func (mr *MockIPostRepositoryMockRecorder) PurgePost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgePost", reflect.TypeOf((*MockIPostRepository)(nil).PurgePost), ctx, id)
+}
+
+// PurgeTrashedPosts mocks base method.
+func (m *MockIPostRepository) PurgeTrashedPosts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrashedPosts", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrashedPosts indicates an expected call of PurgeTrashedPosts.
+func (mr *MockIPostRepositoryMockRecorder) PurgeTrashedPosts(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrashedPosts", reflect.TypeOf((*MockIPostRepository)(nil).PurgeTrashedPosts), ctx, deletedBefore)
+}
+
+// RestorePost mocks base method.
+func (m *MockIPostRepository) RestorePost(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePost", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePost indicates an expected call of RestorePost.
+func (mr *MockIPostRepositoryMockRecorder) RestorePost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePost", reflect.TypeOf((*MockIPostRepository)(nil).RestorePost), ctx, id)
+}
+
+// SearchPosts mocks base method.
+func (m *MockIPostRepository) SearchPosts(ctx context.Context, query string, limit, offset int) ([]model.PostSearchResult, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, query, limit, offset)
+	ret0, _ := ret[0].([]model.PostSearchResult)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockIPostRepositoryMockRecorder) SearchPosts(ctx, query, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockIPostRepository)(nil).SearchPosts), ctx, query, limit, offset)
 }
 
 // UpdatePost mocks base method.
@@ -116,3 +318,31 @@ func (mr *MockIPostRepositoryMockRecorder) UpdatePost(ctx, req any, tagsToBeDele
 	varargs := append([]any{ctx, req}, tagsToBeDeleted...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockIPostRepository)(nil).UpdatePost), varargs...)
 }
+
+// UpdatePostStatus mocks base method.
+func (m *MockIPostRepository) UpdatePostStatus(ctx context.Context, req model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostStatus", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePostStatus indicates an expected call of UpdatePostStatus.
+func (mr *MockIPostRepositoryMockRecorder) UpdatePostStatus(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostStatus", reflect.TypeOf((*MockIPostRepository)(nil).UpdatePostStatus), ctx, req)
+}
+
+// UpdateRenderedContent mocks base method.
+func (m *MockIPostRepository) UpdateRenderedContent(ctx context.Context, req model.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRenderedContent", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRenderedContent indicates an expected call of UpdateRenderedContent.
+func (mr *MockIPostRepositoryMockRecorder) UpdateRenderedContent(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRenderedContent", reflect.TypeOf((*MockIPostRepository)(nil).UpdateRenderedContent), ctx, req)
+}
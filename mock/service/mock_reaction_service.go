@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reaction_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source reaction_service.go -destination ../mock/service/mock_reaction_service.go -package service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/elangreza14/assetfindr-test/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIReactionRepository is a mock of IReactionRepository interface.
+type MockIReactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReactionRepositoryMockRecorder
+}
+
+// MockIReactionRepositoryMockRecorder is the mock recorder for MockIReactionRepository.
+type MockIReactionRepositoryMockRecorder struct {
+	mock *MockIReactionRepository
+}
+
+// NewMockIReactionRepository creates a new mock instance.
+func NewMockIReactionRepository(ctrl *gomock.Controller) *MockIReactionRepository {
+	mock := &MockIReactionRepository{ctrl: ctrl}
+	mock.recorder = &MockIReactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReactionRepository) EXPECT() *MockIReactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddReaction mocks base method.
+func (m *MockIReactionRepository) AddReaction(ctx context.Context, req model.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockIReactionRepositoryMockRecorder) AddReaction(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockIReactionRepository)(nil).AddReaction), ctx, req)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockIReactionRepository) RemoveReaction(ctx context.Context, req model.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockIReactionRepositoryMockRecorder) RemoveReaction(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockIReactionRepository)(nil).RemoveReaction), ctx, req)
+}
+
+// MockIReactionPostRepository is a mock of IReactionPostRepository interface.
+type MockIReactionPostRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReactionPostRepositoryMockRecorder
+}
+
+// MockIReactionPostRepositoryMockRecorder is the mock recorder for MockIReactionPostRepository.
+type MockIReactionPostRepositoryMockRecorder struct {
+	mock *MockIReactionPostRepository
+}
+
+// NewMockIReactionPostRepository creates a new mock instance.
+func NewMockIReactionPostRepository(ctrl *gomock.Controller) *MockIReactionPostRepository {
+	mock := &MockIReactionPostRepository{ctrl: ctrl}
+	mock.recorder = &MockIReactionPostRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReactionPostRepository) EXPECT() *MockIReactionPostRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPost mocks base method.
+func (m *MockIReactionPostRepository) GetPost(ctx context.Context, id int) (*model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPost", ctx, id)
+	ret0, _ := ret[0].(*model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPost indicates an expected call of GetPost.
+func (mr *MockIReactionPostRepositoryMockRecorder) GetPost(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockIReactionPostRepository)(nil).GetPost), ctx, id)
+}
@@ -0,0 +1,190 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tag_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source tag_service.go -destination ../mock/service/mock_tag_service.go -package service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/elangreza14/assetfindr-test/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockITagRepository is a mock of ITagRepository interface.
+type MockITagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITagRepositoryMockRecorder
+}
+
+// MockITagRepositoryMockRecorder is the mock recorder for MockITagRepository.
+type MockITagRepositoryMockRecorder struct {
+	mock *MockITagRepository
+}
+
+// NewMockITagRepository creates a new mock instance.
+func NewMockITagRepository(ctrl *gomock.Controller) *MockITagRepository {
+	mock := &MockITagRepository{ctrl: ctrl}
+	mock.recorder = &MockITagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITagRepository) EXPECT() *MockITagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrphanTags mocks base method.
+func (m *MockITagRepository) DeleteOrphanTags(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrphanTags", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOrphanTags indicates an expected call of DeleteOrphanTags.
+func (mr *MockITagRepositoryMockRecorder) DeleteOrphanTags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphanTags", reflect.TypeOf((*MockITagRepository)(nil).DeleteOrphanTags), ctx)
+}
+
+// DeleteTag mocks base method.
+func (m *MockITagRepository) DeleteTag(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockITagRepositoryMockRecorder) DeleteTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockITagRepository)(nil).DeleteTag), ctx, id)
+}
+
+// GetAllTags mocks base method.
+func (m *MockITagRepository) GetAllTags(ctx context.Context) ([]model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTags", ctx)
+	ret0, _ := ret[0].([]model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTags indicates an expected call of GetAllTags.
+func (mr *MockITagRepositoryMockRecorder) GetAllTags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTags", reflect.TypeOf((*MockITagRepository)(nil).GetAllTags), ctx)
+}
+
+// GetTag mocks base method.
+func (m *MockITagRepository) GetTag(ctx context.Context, id int) (*model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, id)
+	ret0, _ := ret[0].(*model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockITagRepositoryMockRecorder) GetTag(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockITagRepository)(nil).GetTag), ctx, id)
+}
+
+// GetTagByLabel mocks base method.
+func (m *MockITagRepository) GetTagByLabel(ctx context.Context, label string) (*model.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByLabel", ctx, label)
+	ret0, _ := ret[0].(*model.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByLabel indicates an expected call of GetTagByLabel.
+func (mr *MockITagRepositoryMockRecorder) GetTagByLabel(ctx, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByLabel", reflect.TypeOf((*MockITagRepository)(nil).GetTagByLabel), ctx, label)
+}
+
+// GetTagPosts mocks base method.
+func (m *MockITagRepository) GetTagPosts(ctx context.Context, id, limit, offset int) ([]model.Post, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagPosts", ctx, id, limit, offset)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTagPosts indicates an expected call of GetTagPosts.
+func (mr *MockITagRepositoryMockRecorder) GetTagPosts(ctx, id, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagPosts", reflect.TypeOf((*MockITagRepository)(nil).GetTagPosts), ctx, id, limit, offset)
+}
+
+// GetTagSummary mocks base method.
+func (m *MockITagRepository) GetTagSummary(ctx context.Context, id int) (*model.TagSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagSummary", ctx, id)
+	ret0, _ := ret[0].(*model.TagSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagSummary indicates an expected call of GetTagSummary.
+func (mr *MockITagRepositoryMockRecorder) GetTagSummary(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagSummary", reflect.TypeOf((*MockITagRepository)(nil).GetTagSummary), ctx, id)
+}
+
+// GetTags mocks base method.
+func (m *MockITagRepository) GetTags(ctx context.Context, limit, offset int) ([]model.TagSummary, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, limit, offset)
+	ret0, _ := ret[0].([]model.TagSummary)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockITagRepositoryMockRecorder) GetTags(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockITagRepository)(nil).GetTags), ctx, limit, offset)
+}
+
+// MergeTag mocks base method.
+func (m *MockITagRepository) MergeTag(ctx context.Context, sourceID, targetID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTag", ctx, sourceID, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeTag indicates an expected call of MergeTag.
+func (mr *MockITagRepositoryMockRecorder) MergeTag(ctx, sourceID, targetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTag", reflect.TypeOf((*MockITagRepository)(nil).MergeTag), ctx, sourceID, targetID)
+}
+
+// UpdateTag mocks base method.
+func (m *MockITagRepository) UpdateTag(ctx context.Context, req model.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockITagRepositoryMockRecorder) UpdateTag(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockITagRepository)(nil).UpdateTag), ctx, req)
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// APIKey lets machine clients act on behalf of a user, limited to its scopes.
+// Only the hash of the key is stored, the key itself is shown once when it is
+// created.
+type APIKey struct {
+	ID     int    `gorm:"primaryKey"`
+	UserID int    `gorm:"not null;index"`
+	User   *User  `gorm:"constraint:OnDelete:CASCADE"`
+	Name   string `gorm:"not null"`
+	// Prefix is the start of the key, shown to tell the keys apart.
+	Prefix     string   `gorm:"not null"`
+	Hash       string   `gorm:"not null;uniqueIndex"`
+	Scopes     []string `gorm:"type:jsonb;serializer:json"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+}
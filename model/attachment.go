@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attachment is a file uploaded to a post, its content is kept in the blob
+// store under Key.
+type Attachment struct {
+	ID     int `gorm:"primaryKey"`
+	PostID int `gorm:"not null;index"`
+	// UserID is the uploader of the attachment, it is nil once the user is
+	// deleted.
+	UserID      *int   `gorm:"index"`
+	User        *User  `gorm:"constraint:OnDelete:SET NULL"`
+	Filename    string `gorm:"not null"`
+	ContentType string `gorm:"not null"`
+	Size        int64  `gorm:"not null"`
+	Key         string `gorm:"not null;uniqueIndex"`
+	// Checksum is the hex encoded sha256 of the content.
+	Checksum  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+	// DeletedAt is set when the post of the attachment is moved to the trash,
+	// the attachment comes back when the post is restored.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment is a message left on a post, either at the top of its discussion or
+// as a reply to another comment of the same post.
+type Comment struct {
+	ID     int `gorm:"primaryKey"`
+	PostID int `gorm:"not null;index"`
+	// UserID is the author of the comment, it is nil once the user is
+	// deleted.
+	UserID *int  `gorm:"index"`
+	User   *User `gorm:"constraint:OnDelete:SET NULL"`
+	// ParentID is the comment replied to, deleting it deletes its replies.
+	ParentID  *int      `gorm:"index"`
+	Parent    *Comment  `gorm:"constraint:OnDelete:CASCADE"`
+	Body      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+	// DeletedAt is set when the post of the comment is moved to the trash, the
+	// comment comes back when the post is restored.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -1,8 +1,142 @@
 package model
 
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict is returned when a post is written against a version
+// that is not its current one anymore.
+var ErrVersionConflict = errors.New("post version conflict")
+
+// PostStatus is the stage of the lifecycle of a post, only published posts
+// are listed.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// ContentFormat is the markup the content of a post is written in.
+type ContentFormat string
+
+const (
+	ContentFormatPlain    ContentFormat = "plain"
+	ContentFormatMarkdown ContentFormat = "markdown"
+)
+
 type Post struct {
-	ID      int `gorm:"primaryKey"`
-	Title   string
+	ID    int `gorm:"primaryKey"`
+	Title string
+	// Slug identifies the post in urls, unique among the slugs and the old
+	// slugs of every post. It is empty for the posts created before slugs
+	// existed, until they are updated.
+	Slug    string `gorm:"uniqueIndex;default:null"`
 	Content string
-	Tags    []*Tag `gorm:"many2many:post_tags;"`
+	// ContentFormat defaults to plain in the database, as the posts created
+	// before the formats existed were plain text.
+	ContentFormat ContentFormat `gorm:"not null;default:plain"`
+	// ContentHTML is the content rendered in its format and sanitized, it is
+	// written along with the content so that reads never render. The posts
+	// created before it existed are rendered by the -render-posts migration.
+	ContentHTML string
+	// Excerpt, WordCount and ReadingTime, in minutes, are derived from the
+	// text of the content when it is written, so that lists can do without
+	// the content. They are stored along with ContentHTML, WordCount is only
+	// null for the posts that were never rendered.
+	Excerpt     string
+	WordCount   int
+	ReadingTime int
+	Tags        []*Tag `gorm:"many2many:post_tags;"`
+	// UserID is the author of the post, it is nil for the posts created
+	// before users existed, which anyone can change.
+	UserID *int  `gorm:"index"`
+	User   *User `gorm:"constraint:OnDelete:SET NULL"`
+	// ReactionCounts holds the number of reactions of each kind the post
+	// received, the kinds without reactions are missing.
+	ReactionCounts []PostReactionCount
+	// Comments are only loaded when they are asked for.
+	Comments []Comment
+	// Status defaults to published in the database so that the posts created
+	// before the lifecycle existed stay listed, new posts are created as
+	// drafts.
+	Status PostStatus `gorm:"not null;default:published;index"`
+	// PublishedAt is set the first time the post is published.
+	PublishedAt *time.Time
+	// PublishAt schedules a draft to be published automatically, it is
+	// cleared once the post is published.
+	PublishAt *time.Time `gorm:"index"`
+	// Version is incremented by every update of the post, including the
+	// renames of its tags.
+	Version   int       `gorm:"not null;default:1"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+	UpdatedAt time.Time `gorm:"not null;default:now();index"`
+	// DeletedAt is set when the post is moved to the trash, the post is
+	// excluded from every query unless they are unscoped.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// SearchVector is generated by postgres from Title and Content and is
+	// never read or written by the application, only searched.
+	SearchVector string `gorm:"->:false;<-:false;type:tsvector GENERATED ALWAYS AS (to_tsvector('english', coalesce(title, '') || ' ' || coalesce(content, ''))) STORED;index:idx_posts_search_vector,type:gin"`
+}
+
+// PostFilter holds the listing options applied by the post repository.
+type PostFilter struct {
+	Limit  int
+	Offset int
+	// BeforeID seeks to posts with an id lower than it instead of using
+	// Offset, it is ignored when zero.
+	BeforeID int
+	// Tags keeps only posts labeled with any of the tags, or with all of
+	// them when MatchAllTags is set. Tags are compared in lower case.
+	Tags         []string
+	MatchAllTags bool
+	// Status keeps only the posts in the status, it is ignored when empty.
+	Status PostStatus
+	// AuthorID keeps only the posts of the user, it is ignored when zero.
+	AuthorID int
+	// Fields picks what is loaded of the posts, they are loaded whole, but
+	// for their comments, when it is nil.
+	Fields *PostFields
+}
+
+// PostFields picks the columns of the posts loaded and the relations loaded
+// along with them.
+type PostFields struct {
+	// Columns are the columns of the posts loaded, all of them when empty.
+	Columns   []string
+	Tags      bool
+	Author    bool
+	Reactions bool
+	Comments  bool
+	// CommentsLimit is how many of the oldest comments of each post are
+	// loaded along with it.
+	CommentsLimit int
+}
+
+// PostsSummary summarizes the posts matching a filter, it changes whenever
+// one of them is created, updated or deleted.
+type PostsSummary struct {
+	Total         int64
+	LastUpdatedAt time.Time
+}
+
+// SnippetStartSel and SnippetStopSel delimit the matches in the snippets of
+// the search results. They are control characters rather than HTML, so that
+// the matches are told apart from the raw content around them.
+const (
+	SnippetStartSel = "\x02"
+	SnippetStopSel  = "\x03"
+)
+
+// PostSearchResult is a post matching a full text search, along with its
+// relevance and a fragment of its raw content, its matches delimited by
+// SnippetStartSel and SnippetStopSel.
+type PostSearchResult struct {
+	Post    Post
+	Rank    float64
+	Snippet string
 }
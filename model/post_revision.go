@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// PostRevision is an immutable snapshot of a post, taken in the transaction
+// of every update of the post. Revision is the version the post had when the
+// snapshot was taken, read from the post locked in that transaction, so a
+// failed update leaves no revision behind. The numbers have gaps though, since
+// the status changes, the restores from the trash and the changes of the tags
+// of the post bump its version without taking a snapshot.
+type PostRevision struct {
+	ID       int `gorm:"primaryKey"`
+	PostID   int `gorm:"not null;uniqueIndex:idx_post_revisions_post_revision"`
+	Revision int `gorm:"not null;uniqueIndex:idx_post_revisions_post_revision"`
+	Title    string
+	Content  string
+	// ContentFormat is the format of Content, the revisions taken before
+	// the formats existed are plain text.
+	ContentFormat ContentFormat `gorm:"not null;default:plain"`
+	Tags          []string      `gorm:"type:jsonb;serializer:json"`
+	CreatedAt     time.Time     `gorm:"not null;default:now()"`
+}
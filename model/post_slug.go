@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PostSlug is a slug a post had before its title changed, kept so that the
+// links to the post keep leading to it. A post can take one of its own old
+// slugs back, never the one of another post.
+type PostSlug struct {
+	ID        int       `gorm:"primaryKey"`
+	PostID    int       `gorm:"not null;index"`
+	Slug      string    `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
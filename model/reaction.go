@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// ReactionKind is how a user reacts to a post.
+type ReactionKind string
+
+const (
+	ReactionLike       ReactionKind = "like"
+	ReactionLove       ReactionKind = "love"
+	ReactionLaugh      ReactionKind = "laugh"
+	ReactionInsightful ReactionKind = "insightful"
+)
+
+// Reaction is a reaction of a user to a post, a user reacts at most once
+// with each kind.
+type Reaction struct {
+	PostID    int          `gorm:"primaryKey"`
+	UserID    int          `gorm:"primaryKey;index"`
+	Kind      ReactionKind `gorm:"primaryKey"`
+	CreatedAt time.Time    `gorm:"not null;default:now()"`
+}
+
+// PostReactionCount counts the reactions of a kind to a post. It is updated
+// in the transaction adding or removing a reaction, so that the posts are
+// listed without counting their reactions.
+type PostReactionCount struct {
+	PostID int          `gorm:"primaryKey"`
+	Kind   ReactionKind `gorm:"primaryKey"`
+	Count  int          `gorm:"not null;default:0"`
+}
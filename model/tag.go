@@ -1,7 +1,23 @@
 package model
 
+import "errors"
+
+// ErrLastTag is returned when a tag cannot be removed because it is the only
+// tag of a post, which must keep at least one.
+var ErrLastTag = errors.New("tag is the last tag of a post")
+
 type Tag struct {
-	ID    int     `gorm:"primaryKey"`
-	Label string  `gorm:"uniqueIndex:idx_label_tag"`
+	ID int `gorm:"primaryKey"`
+	// Label is unique regardless of its case.
+	Label string  `gorm:"index:idx_tags_label_lower,unique,expression:lower(label)"`
+	Slug  string  `gorm:"index"`
 	Posts []*Post `gorm:"many2many:post_tags;"`
 }
+
+// TagSummary is a tag along with the number of published posts labeled with
+// it.
+type TagSummary struct {
+	ID        int
+	Label     string
+	PostCount int64
+}
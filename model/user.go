@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// User is a person using the application, most of them authors of posts.
+type User struct {
+	ID    int    `gorm:"primaryKey"`
+	Name  string `gorm:"not null"`
+	Email string `gorm:"not null;uniqueIndex"`
+	// Role gives the permissions of the user, every user is an author until
+	// an admin changes it.
+	Role      string    `gorm:"not null;default:author"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
@@ -0,0 +1,87 @@
+package normalizer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CaseMode decides how the case of a tag label is stored.
+type CaseMode string
+
+const (
+	// CaseLower stores labels in lower case.
+	CaseLower CaseMode = "lower"
+	// CaseFold stores labels case folded, which is more aggressive than
+	// lower case for some scripts, e.g. "ß" becomes "ss".
+	CaseFold CaseMode = "fold"
+	// CasePreserve stores labels with the case they were sent with.
+	CasePreserve CaseMode = "preserve"
+)
+
+type TagNormalizer struct {
+	caseMode CaseMode
+}
+
+// NewTagNormalizer builds a normalizer for mode, an unknown mode falls back to
+// CaseLower.
+func NewTagNormalizer(mode CaseMode) TagNormalizer {
+	switch mode {
+	case CaseFold, CasePreserve:
+	default:
+		mode = CaseLower
+	}
+
+	return TagNormalizer{caseMode: mode}
+}
+
+// Label returns the label to be displayed and stored for raw: trimmed, with
+// inner whitespace collapsed, in unicode NFC and cased per the case mode.
+func (tn TagNormalizer) Label(raw string) string {
+	label := norm.NFC.String(strings.Join(strings.Fields(raw), " "))
+
+	switch tn.caseMode {
+	case CaseFold:
+		return cases.Fold().String(label)
+	case CasePreserve:
+		return label
+	default:
+		return cases.Lower(language.Und).String(label)
+	}
+}
+
+// Key identifies labels that are the same tag regardless of their case. It
+// mirrors the lower(label) unique index of the tags table.
+func Key(label string) string {
+	return strings.ToLower(label)
+}
+
+// Slug turns s into a lower case, url safe form made of letters and digits
+// separated by dashes, with diacritics removed, e.g. "Héllo, World" becomes
+// "hello-world".
+func Slug(s string) string {
+	s, _, _ = transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn))), s)
+	s = strings.ToLower(s)
+
+	var b strings.Builder
+	separate := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+
+		if separate && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		separate = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package normalizer_test
+
+import (
+	"testing"
+
+	. "github.com/elangreza14/assetfindr-test/normalizer"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestTagNormalizerSuite struct {
+	suite.Suite
+}
+
+func TestTagNormalizerTestSuite(t *testing.T) {
+	suite.Run(t, new(TestTagNormalizerSuite))
+}
+
+func (suite *TestTagNormalizerSuite) TestTagNormalizer_Label() {
+	testCases := []struct {
+		name     string
+		mode     CaseMode
+		label    string
+		expected string
+	}{
+		{"trim and collapse spaces", CaseLower, "  Go \t Lang ", "go lang"},
+		{"nfc", CasePreserve, "Café", "Café"},
+		{"lower", CaseLower, "STRASSE Straße", "strasse straße"},
+		{"fold", CaseFold, "STRASSE Straße", "strasse strasse"},
+		{"preserve", CasePreserve, "GoLang", "GoLang"},
+		{"unknown mode is lower", CaseMode("upper"), "GoLang", "golang"},
+		{"blank", CaseLower, " \n ", ""},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.Equal(tc.expected, NewTagNormalizer(tc.mode).Label(tc.label))
+		})
+	}
+}
+
+func (suite *TestTagNormalizerSuite) TestTagNormalizer_Key() {
+	suite.Equal(Key("Go"), Key("gO"))
+	suite.NotEqual(Key("go"), Key("golang"))
+}
+
+func (suite *TestTagNormalizerSuite) TestTagNormalizer_Slug() {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"Héllo, World!", "hello-world"},
+		{"  go -- lang  ", "go-lang"},
+		{"C++", "c"},
+		{"日本 語", "日本-語"},
+		{"!!!", ""},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.value, func() {
+			suite.Equal(tc.expected, Slug(tc.value))
+		})
+	}
+}
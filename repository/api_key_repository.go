@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateAPIKey creates the key, returning it with its id.
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, req model.APIKey) (*model.APIKey, error) {
+	apiKey := model.APIKey{
+		UserID: req.UserID,
+		Name:   req.Name,
+		Prefix: req.Prefix,
+		Hash:   req.Hash,
+		Scopes: req.Scopes,
+	}
+
+	err := ar.db.WithContext(ctx).Create(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// GetAPIKeys lists the keys of the user, including the revoked ones, the
+// latest first.
+func (ar *APIKeyRepository) GetAPIKeys(ctx context.Context, userID, limit, offset int) ([]model.APIKey, int64, error) {
+	var total int64
+	err := ar.db.WithContext(ctx).Model(&model.APIKey{}).Where("user_id = ?", userID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.APIKey{}
+	err = ar.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (ar *APIKeyRepository) GetAPIKey(ctx context.Context, id int) (*model.APIKey, error) {
+	res := model.APIKey{}
+	err := ar.db.WithContext(ctx).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetAPIKeyByHash gets the key with the hash, along with its user.
+func (ar *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	res := model.APIKey{}
+	err := ar.db.WithContext(ctx).Preload("User").Where("hash = ?", hash).First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// RevokeAPIKey revokes the key, a key already revoked keeps its revocation
+// time.
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error {
+	return ar.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error
+}
+
+// TouchAPIKey records that the key was used at usedAt. The key is only
+// written when it was last used before usedBefore, so that a busy key is not
+// written on every request.
+func (ar *APIKeyRepository) TouchAPIKey(ctx context.Context, id int, usedAt, usedBefore time.Time) error {
+	return ar.db.WithContext(ctx).Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedBefore).
+		Update("last_used_at", usedAt).Error
+}
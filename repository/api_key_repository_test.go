@@ -0,0 +1,138 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TestAPIKeyRepositorySuite struct {
+	suite.Suite
+
+	sqlDB      *sql.DB
+	gormDB     *gorm.DB
+	mock       sqlmock.Sqlmock
+	apiKeyRepo *APIKeyRepository
+}
+
+func (suite *TestAPIKeyRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.apiKeyRepo = NewAPIKeyRepository(gormDB)
+}
+
+func (suite *TestAPIKeyRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestAPIKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAPIKeyRepositorySuite))
+}
+
+func (suite *TestAPIKeyRepositorySuite) TestAPIKeyRepository_CreateAPIKey() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`INSERT INTO "api_keys" ("user_id","name","prefix","hash","scopes","last_used_at","revoked_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "created_at","id"`)).
+			WithArgs(7, "ingestion", "afk_abcd", "hash", `["posts:write"]`, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		suite.mock.ExpectCommit()
+
+		res, err := suite.apiKeyRepo.CreateAPIKey(context.Background(), model.APIKey{
+			UserID: 7,
+			Name:   "ingestion",
+			Prefix: "afk_abcd",
+			Hash:   "hash",
+			Scopes: []string{"posts:write"},
+		})
+		suite.NoError(err)
+		suite.Equal(1, res.ID)
+	})
+}
+
+func (suite *TestAPIKeyRepositorySuite) TestAPIKeyRepository_GetAPIKeys() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "api_keys" WHERE user_id = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "api_keys" WHERE user_id = $1 ORDER BY id desc LIMIT $2`)).
+			WithArgs(7, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes"}).AddRow(1, 7, "ingestion", `["posts:write"]`))
+
+		res, total, err := suite.apiKeyRepo.GetAPIKeys(context.Background(), 7, 10, 0)
+		suite.NoError(err)
+		suite.Equal(int64(1), total)
+		suite.Equal([]string{"posts:write"}, res[0].Scopes)
+	})
+}
+
+func (suite *TestAPIKeyRepositorySuite) TestAPIKeyRepository_GetAPIKeyByHash() {
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "api_keys" WHERE hash = $1 ORDER BY "api_keys"."id" LIMIT $2`)).
+			WithArgs("hash", 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		res, err := suite.apiKeyRepo.GetAPIKeyByHash(context.Background(), "hash")
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "api_keys" WHERE hash = $1 ORDER BY "api_keys"."id" LIMIT $2`)).
+			WithArgs("hash", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 7))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(7, "editor"))
+
+		res, err := suite.apiKeyRepo.GetAPIKeyByHash(context.Background(), "hash")
+		suite.NoError(err)
+		suite.Equal("editor", res.User.Role)
+	})
+}
+
+func (suite *TestAPIKeyRepositorySuite) TestAPIKeyRepository_RevokeAPIKey() {
+	suite.Run("success", func() {
+		revokedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "api_keys" SET "revoked_at"=$1 WHERE id = $2 AND revoked_at IS NULL`)).
+			WithArgs(revokedAt, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.apiKeyRepo.RevokeAPIKey(context.Background(), 1, revokedAt)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestAPIKeyRepositorySuite) TestAPIKeyRepository_TouchAPIKey() {
+	suite.Run("success", func() {
+		usedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "api_keys" SET "last_used_at"=$1 WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)`)).
+			WithArgs(usedAt, 1, usedAt.Add(-time.Minute)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectCommit()
+
+		err := suite.apiKeyRepo.TouchAPIKey(context.Background(), 1, usedAt, usedAt.Add(-time.Minute))
+		suite.NoError(err)
+	})
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// CreateAttachment creates the attachment, returning it with its id.
+func (ar *AttachmentRepository) CreateAttachment(ctx context.Context, req model.Attachment) (*model.Attachment, error) {
+	attachment := model.Attachment{
+		PostID:      req.PostID,
+		UserID:      req.UserID,
+		Filename:    req.Filename,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Key:         req.Key,
+		Checksum:    req.Checksum,
+	}
+
+	err := ar.db.WithContext(ctx).Create(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// GetAttachments lists the attachments of the post, the oldest first.
+func (ar *AttachmentRepository) GetAttachments(ctx context.Context, postID, limit, offset int) ([]model.Attachment, int64, error) {
+	var total int64
+	err := ar.db.WithContext(ctx).Model(&model.Attachment{}).
+		Where("post_id = ?", postID).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.Attachment{}
+	err = ar.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (ar *AttachmentRepository) GetAttachment(ctx context.Context, postID, id int) (*model.Attachment, error) {
+	res := model.Attachment{}
+	err := ar.db.WithContext(ctx).Where("post_id = ?", postID).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// DeleteAttachment deletes the attachment for good, its blob must be deleted
+// from the blob store by the caller.
+func (ar *AttachmentRepository) DeleteAttachment(ctx context.Context, id int) error {
+	return ar.db.WithContext(ctx).Unscoped().Delete(&model.Attachment{}, id).Error
+}
+
+// GetOrphanAttachments gets at most limit attachments whose post was purged,
+// their blobs are still to be deleted.
+func (ar *AttachmentRepository) GetOrphanAttachments(ctx context.Context, limit int) ([]model.Attachment, error) {
+	res := []model.Attachment{}
+	err := ar.db.WithContext(ctx).Unscoped().
+		Where("NOT EXISTS (SELECT 1 FROM posts WHERE posts.id = attachments.post_id)").
+		Order("id").
+		Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TestAttachmentRepositorySuite struct {
+	suite.Suite
+
+	sqlDB          *sql.DB
+	gormDB         *gorm.DB
+	mock           sqlmock.Sqlmock
+	attachmentRepo *AttachmentRepository
+}
+
+func (suite *TestAttachmentRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.attachmentRepo = NewAttachmentRepository(gormDB)
+}
+
+func (suite *TestAttachmentRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestAttachmentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAttachmentRepositorySuite))
+}
+
+func (suite *TestAttachmentRepositorySuite) TestAttachmentRepository_CreateAttachment() {
+	userID := 7
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`INSERT INTO "attachments" ("post_id","user_id","filename","content_type","size","key","checksum","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "created_at","id"`)).
+			WithArgs(1, userID, "cat.png", "image/png", int64(5), "posts/1/abc", "sum", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(time.Now(), 3))
+		suite.mock.ExpectCommit()
+
+		res, err := suite.attachmentRepo.CreateAttachment(context.Background(), model.Attachment{
+			PostID:      1,
+			UserID:      &userID,
+			Filename:    "cat.png",
+			ContentType: "image/png",
+			Size:        5,
+			Key:         "posts/1/abc",
+			Checksum:    "sum",
+		})
+		suite.NoError(err)
+		suite.Equal(3, res.ID)
+	})
+}
+
+func (suite *TestAttachmentRepositorySuite) TestAttachmentRepository_GetAttachments() {
+	countSQL := regexp.QuoteMeta(
+		`SELECT count(*) FROM "attachments" WHERE post_id = $1 AND "attachments"."deleted_at" IS NULL`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "attachments" WHERE post_id = $1 AND "attachments"."deleted_at" IS NULL ORDER BY id LIMIT $2 OFFSET $3`)).
+			WithArgs(1, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "filename"}).AddRow(4, 1, "cat.png"))
+
+		res, total, err := suite.attachmentRepo.GetAttachments(context.Background(), 1, 1, 2)
+		suite.NoError(err)
+		suite.Equal(int64(3), total)
+		suite.Len(res, 1)
+		suite.Equal("cat.png", res[0].Filename)
+	})
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(1).
+			WillReturnError(errors.New("err"))
+
+		_, _, err := suite.attachmentRepo.GetAttachments(context.Background(), 1, 1, 2)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestAttachmentRepositorySuite) TestAttachmentRepository_GetAttachment() {
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "attachments" WHERE post_id = $1 AND "attachments"."id" = $2 AND "attachments"."deleted_at" IS NULL ORDER BY "attachments"."id" LIMIT $3`)).
+			WithArgs(1, 3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := suite.attachmentRepo.GetAttachment(context.Background(), 1, 3)
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (suite *TestAttachmentRepositorySuite) TestAttachmentRepository_DeleteAttachment() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "attachments" WHERE "attachments"."id" = $1`)).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.attachmentRepo.DeleteAttachment(context.Background(), 3)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestAttachmentRepositorySuite) TestAttachmentRepository_GetOrphanAttachments() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "attachments" WHERE NOT EXISTS (SELECT 1 FROM posts WHERE posts.id = attachments.post_id) ORDER BY id LIMIT $1`)).
+			WithArgs(100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "key"}).AddRow(4, 1, "posts/1/abc"))
+
+		res, err := suite.attachmentRepo.GetOrphanAttachments(context.Background(), 100)
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal("posts/1/abc", res[0].Key)
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// CreateComment creates the comment and touches its post in the same
+// transaction, returning the comment with its id.
+func (cr *CommentRepository) CreateComment(ctx context.Context, req model.Comment) (*model.Comment, error) {
+	comment := model.Comment{
+		PostID:   req.PostID,
+		UserID:   req.UserID,
+		ParentID: req.ParentID,
+		Body:     req.Body,
+	}
+
+	err := cr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&comment).Error
+		if err != nil {
+			return err
+		}
+
+		return touchPost(tx, comment.PostID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetComments lists the comments starting a thread on the post, the oldest
+// first, without their replies.
+func (cr *CommentRepository) GetComments(ctx context.Context, postID, limit, offset int) ([]model.Comment, int64, error) {
+	var total int64
+	err := cr.db.WithContext(ctx).Model(&model.Comment{}).
+		Where("post_id = ? AND parent_id IS NULL", postID).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.Comment{}
+	err = cr.db.WithContext(ctx).
+		Where("post_id = ? AND parent_id IS NULL", postID).
+		Preload("User").
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+// GetCommentReplies gets every reply to the comments, along with the replies
+// to the replies, the oldest first.
+func (cr *CommentRepository) GetCommentReplies(ctx context.Context, ids []int) ([]model.Comment, error) {
+	res := []model.Comment{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	err := cr.db.WithContext(ctx).
+		Where(`id IN (
+			WITH RECURSIVE replies AS (
+				SELECT id FROM comments WHERE parent_id IN ?
+				UNION ALL
+				SELECT comments.id FROM comments JOIN replies ON comments.parent_id = replies.id
+			)
+			SELECT id FROM replies
+		)`, ids).
+		Preload("User").
+		Order("id").
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (cr *CommentRepository) GetComment(ctx context.Context, postID, id int) (*model.Comment, error) {
+	res := model.Comment{}
+	err := cr.db.WithContext(ctx).Preload("User").Where("post_id = ?", postID).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// DeleteComment deletes the comment for good and touches its post in the same
+// transaction, the database deletes its replies along with it.
+func (cr *CommentRepository) DeleteComment(ctx context.Context, id int) error {
+	return cr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE posts SET updated_at = now() WHERE id = (SELECT post_id FROM comments WHERE id = $1);`, id).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(&model.Comment{}, id).Error
+	})
+}
@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TestCommentRepositorySuite struct {
+	suite.Suite
+
+	sqlDB       *sql.DB
+	gormDB      *gorm.DB
+	mock        sqlmock.Sqlmock
+	commentRepo *CommentRepository
+}
+
+func (suite *TestCommentRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.commentRepo = NewCommentRepository(gormDB)
+}
+
+func (suite *TestCommentRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestCommentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestCommentRepositorySuite))
+}
+
+func (suite *TestCommentRepositorySuite) TestCommentRepository_CreateComment() {
+	userID, parentID := 7, 2
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`INSERT INTO "comments" ("post_id","user_id","parent_id","body","deleted_at") VALUES ($1,$2,$3,$4,$5) RETURNING "created_at","id"`)).
+			WithArgs(1, userID, parentID, "nice", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(time.Now(), 3))
+		suite.mock.ExpectExec(touchPostSQL).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		res, err := suite.commentRepo.CreateComment(context.Background(), model.Comment{
+			PostID:   1,
+			UserID:   &userID,
+			ParentID: &parentID,
+			Body:     "nice",
+		})
+		suite.NoError(err)
+		suite.Equal(3, res.ID)
+	})
+}
+
+func (suite *TestCommentRepositorySuite) TestCommentRepository_GetComments() {
+	countSQL := regexp.QuoteMeta(
+		`SELECT count(*) FROM "comments" WHERE (post_id = $1 AND parent_id IS NULL) AND "comments"."deleted_at" IS NULL`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "comments" WHERE (post_id = $1 AND parent_id IS NULL) AND "comments"."deleted_at" IS NULL ORDER BY id LIMIT $2 OFFSET $3`)).
+			WithArgs(1, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "user_id", "body"}).AddRow(4, 1, 7, "nice"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "jane"))
+
+		res, total, err := suite.commentRepo.GetComments(context.Background(), 1, 1, 2)
+		suite.NoError(err)
+		suite.Equal(int64(3), total)
+		suite.Len(res, 1)
+		suite.Equal("jane", res[0].User.Name)
+	})
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(1).
+			WillReturnError(errors.New("err"))
+
+		_, _, err := suite.commentRepo.GetComments(context.Background(), 1, 1, 2)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestCommentRepositorySuite) TestCommentRepository_GetCommentReplies() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "comments" WHERE id IN ( WITH RECURSIVE replies AS ( SELECT id FROM comments WHERE parent_id IN ($1,$2) UNION ALL SELECT comments.id FROM comments JOIN replies ON comments.parent_id = replies.id ) SELECT id FROM replies ) AND "comments"."deleted_at" IS NULL ORDER BY id`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "body"}).AddRow(3, 1, "reply").AddRow(4, 3, "reply to reply"))
+
+		res, err := suite.commentRepo.GetCommentReplies(context.Background(), []int{1, 2})
+		suite.NoError(err)
+		suite.Len(res, 2)
+	})
+
+	suite.Run("success without comments", func() {
+		res, err := suite.commentRepo.GetCommentReplies(context.Background(), nil)
+		suite.NoError(err)
+		suite.Empty(res)
+	})
+}
+
+func (suite *TestCommentRepositorySuite) TestCommentRepository_GetComment() {
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "comments" WHERE post_id = $1 AND "comments"."id" = $2 AND "comments"."deleted_at" IS NULL ORDER BY "comments"."id" LIMIT $3`)).
+			WithArgs(1, 3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := suite.commentRepo.GetComment(context.Background(), 1, 3)
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (suite *TestCommentRepositorySuite) TestCommentRepository_DeleteComment() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE posts SET updated_at = now() WHERE id = (SELECT post_id FROM comments WHERE id = $1);`)).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comments" WHERE "comments"."id" = $1`)).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.commentRepo.DeleteComment(context.Background(), 3)
+		suite.NoError(err)
+	})
+}
@@ -2,58 +2,240 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/elangreza14/assetfindr-test/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// snippetOptions are the options of the headlines of the search results, the
+// matches being delimited by control characters instead of HTML, as the
+// content is not escaped.
+var snippetOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=2`, model.SnippetStartSel, model.SnippetStopSel)
+
+// PostsPurged counts the posts deleted for good from the trash, published on
+// /debug/vars.
+var PostsPurged = expvar.NewInt("posts_purged_total")
+
 type (
 	PostRepository struct {
-		db *gorm.DB
+		db                *gorm.DB
+		cleanupOrphanTags bool
 	}
+
+	PostRepositoryOption func(*PostRepository)
 )
 
-func NewPostRepository(db *gorm.DB) *PostRepository {
-	return &PostRepository{db}
+// WithOrphanTagCleanup makes the updates and purges of posts remove, in the
+// same transaction, the tags they detached that are not used by any other
+// post.
+func WithOrphanTagCleanup() PostRepositoryOption {
+	return func(pr *PostRepository) {
+		pr.cleanupOrphanTags = true
+	}
 }
 
-func (pr *PostRepository) GetPosts(ctx context.Context) ([]model.Post, error) {
+func NewPostRepository(db *gorm.DB, opts ...PostRepositoryOption) *PostRepository {
+	pr := &PostRepository{db: db}
+
+	for _, opt := range opts {
+		opt(pr)
+	}
+
+	return pr
+}
+
+func (pr *PostRepository) GetPosts(ctx context.Context, filter model.PostFilter) ([]model.Post, int64, error) {
+	var total int64
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).Scopes(pr.filterPosts(filter)).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := pr.db.WithContext(ctx).Model(&model.Post{}).Scopes(pr.filterPosts(filter))
+	if filter.BeforeID > 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	} else {
+		query = query.Offset(filter.Offset)
+	}
+
+	if filter.Fields != nil {
+		query = query.Scopes(selectPostFields(*filter.Fields))
+	} else {
+		query = query.Preload("Tags").Preload("User").Preload("ReactionCounts")
+	}
+
 	res := []model.Post{}
-	err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").Order("id desc").Find(&res).Error
+	err = query.
+		Order("id desc").
+		Limit(filter.Limit).
+		Find(&res).Error
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return res, nil
+	return res, total, nil
 }
 
-func (pr *PostRepository) CreatePost(ctx context.Context, req model.Post) error {
-	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+// GetPostsSummary counts the posts matching filter along with their latest
+// update, regardless of pagination.
+func (pr *PostRepository) GetPostsSummary(ctx context.Context, filter model.PostFilter) (*model.PostsSummary, error) {
+	res := struct {
+		Total         int64
+		LastUpdatedAt sql.NullTime
+	}{}
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).
+		Scopes(pr.filterPosts(filter)).
+		Select("COUNT(*) AS total, MAX(posts.updated_at) AS last_updated_at").
+		Scan(&res).Error
+	if err != nil {
+		return nil, err
+	}
 
-		post := model.Post{
-			Title:   req.Title,
-			Content: req.Content,
+	return &model.PostsSummary{
+		Total:         res.Total,
+		LastUpdatedAt: res.LastUpdatedAt.Time,
+	}, nil
+}
+
+// filterPosts narrows a posts query down to the rows matching filter,
+// regardless of pagination.
+func (pr *PostRepository) filterPosts(filter model.PostFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Status != "" {
+			db = db.Where("posts.status = ?", filter.Status)
 		}
 
-		err := tx.WithContext(ctx).Create(&post).Error
-		if err != nil {
-			return err
+		if filter.AuthorID != 0 {
+			db = db.Where("posts.user_id = ?", filter.AuthorID)
 		}
 
-		for _, tag := range req.Tags {
-			reqTag := model.Tag{}
-			err := tx.Where(model.Tag{Label: tag.Label}).FirstOrCreate(&reqTag).Error
-			if err != nil {
-				return err
+		if len(filter.Tags) > 0 {
+			taggedPosts := pr.db.Table("post_tags").
+				Select("post_tags.post_id").
+				Joins("JOIN tags ON tags.id = post_tags.tag_id").
+				Where("lower(tags.label) IN ?", filter.Tags)
+
+			if filter.MatchAllTags {
+				taggedPosts = taggedPosts.
+					Group("post_tags.post_id").
+					Having("COUNT(DISTINCT tags.id) = ?", len(filter.Tags))
 			}
 
-			err = tx.Exec(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`, post.ID, reqTag.ID).Error
+			db = db.Where("posts.id IN (?)", taggedPosts)
+		}
+
+		return db
+	}
+}
+
+// SearchPosts runs a full text search over post titles and contents, ordered
+// by relevance. The query follows the web search syntax of postgres, so it
+// accepts quoted phrases, "or" and "-" exclusions. Only published posts are
+// searched.
+func (pr *PostRepository) SearchPosts(ctx context.Context, query string, limit, offset int) ([]model.PostSearchResult, int64, error) {
+	var total int64
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).
+		Where("posts.status = ?", model.PostStatusPublished).
+		Where("posts.search_vector @@ websearch_to_tsquery('english', ?)", query).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := []struct {
+		ID      int
+		Rank    float64
+		Snippet string
+	}{}
+	err = pr.db.WithContext(ctx).Model(&model.Post{}).
+		Select(`posts.id,
+			ts_rank(posts.search_vector, websearch_to_tsquery('english', @query)) AS rank,
+			ts_headline('english', posts.content, websearch_to_tsquery('english', @query), @options) AS snippet`,
+			sql.Named("query", query), sql.Named("options", snippetOptions)).
+		Where("posts.status = ?", model.PostStatusPublished).
+		Where("posts.search_vector @@ websearch_to_tsquery('english', ?)", query).
+		Order("rank desc, posts.id desc").
+		Limit(limit).
+		Offset(offset).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(matches) == 0 {
+		return []model.PostSearchResult{}, total, nil
+	}
+
+	ids := make([]int, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+
+	posts := []model.Post{}
+	err = pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").Preload("User").Preload("ReactionCounts").Find(&posts, ids).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	postsByID := make(map[int]model.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.ID] = post
+	}
+
+	res := make([]model.PostSearchResult, 0, len(matches))
+	for _, match := range matches {
+		post, ok := postsByID[match.ID]
+		if !ok {
+			continue
+		}
+
+		res = append(res, model.PostSearchResult{
+			Post:    post,
+			Rank:    match.Rank,
+			Snippet: match.Snippet,
+		})
+	}
+
+	return res, total, nil
+}
+
+func (pr *PostRepository) CreatePost(ctx context.Context, req model.Post) error {
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		post := model.Post{
+			Title:         req.Title,
+			Content:       req.Content,
+			ContentFormat: req.ContentFormat,
+			ContentHTML:   req.ContentHTML,
+			Excerpt:       req.Excerpt,
+			WordCount:     req.WordCount,
+			ReadingTime:   req.ReadingTime,
+			Status:        req.Status,
+			PublishAt:     req.PublishAt,
+			UserID:        req.UserID,
+		}
+
+		var err error
+		if req.Slug != "" {
+			post.Slug, err = uniqueSlug(tx, req.Slug, 0)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		err = tx.WithContext(ctx).Create(&post).Error
+		if err != nil {
+			return err
+		}
+
+		return attachTags(tx, post.ID, req.Tags)
 	})
 
 	if err != nil {
@@ -65,7 +247,7 @@ func (pr *PostRepository) CreatePost(ctx context.Context, req model.Post) error
 
 func (pr *PostRepository) GetPost(ctx context.Context, id int) (*model.Post, error) {
 	res := model.Post{}
-	err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").First(&res, id).Error
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).Preload("Tags").Preload("User").Preload("ReactionCounts").First(&res, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -73,38 +255,91 @@ func (pr *PostRepository) GetPost(ctx context.Context, id int) (*model.Post, err
 	return &res, nil
 }
 
+// GetPostBySlug gets the post with the slug, or the post which had the slug
+// before its title changed.
+func (pr *PostRepository) GetPostBySlug(ctx context.Context, slug string) (*model.Post, error) {
+	res := model.Post{}
+	err := pr.db.WithContext(ctx).Model(&model.Post{}).
+		Preload("Tags").Preload("User").Preload("ReactionCounts").
+		Where("slug = ? OR id IN (?)", slug, pr.db.Model(&model.PostSlug{}).Select("post_id").Where("slug = ?", slug)).
+		First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// UpdatePost replaces the post and its tags, as long as req.Version is still
+// the version of the post. The post is saved as a revision beforehand.
 func (pr *PostRepository) UpdatePost(ctx context.Context, req model.Post, tagsToBeDeleted ...int) error {
 	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		err := tx.WithContext(ctx).Exec(`DELETE FROM post_tags WHERE post_id=? and tag_id IN ?;`, req.ID, tagsToBeDeleted).Error
+		err := savePostRevision(tx, req)
 		if err != nil {
 			return err
 		}
 
-		post := model.Post{
-			ID:      req.ID,
-			Title:   req.Title,
-			Content: req.Content,
+		columns := []string{"title", "content", "content_format", "content_html", "excerpt", "word_count", "reading_time", "publish_at"}
+		if req.Slug != "" {
+			req.Slug, err = changePostSlug(tx, req.ID, req.Slug)
+			if err != nil {
+				return err
+			}
+			columns = append(columns, "slug")
+		}
+
+		err = updatePost(tx, req, columns...)
+		if err != nil {
+			return err
+		}
+
+		err = pr.detachTags(tx, req.ID, tagsToBeDeleted)
+		if err != nil {
+			return err
 		}
 
-		err = tx.WithContext(ctx).Updates(&post).Error
+		return attachTags(tx, req.ID, req.Tags)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PatchPost only updates the given columns of the post, along with its slug
+// when req has one, then attaches the tags of req and detaches
+// tagsToBeDeleted. The version is bumped, and the post saved as a revision,
+// even when only the tags change.
+func (pr *PostRepository) PatchPost(ctx context.Context, req model.Post, columns []string, tagsToBeDeleted ...int) error {
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := savePostRevision(tx, req)
 		if err != nil {
 			return err
 		}
 
-		for _, tag := range req.Tags {
-			reqTag := model.Tag{}
-			err := tx.Where(model.Tag{Label: tag.Label}).FirstOrCreate(&reqTag).Error
+		if req.Slug != "" {
+			req.Slug, err = changePostSlug(tx, req.ID, req.Slug)
 			if err != nil {
 				return err
 			}
+			columns = append(columns[:len(columns):len(columns)], "slug")
+		}
+
+		err = updatePost(tx, req, columns...)
+		if err != nil {
+			return err
+		}
 
-			err = tx.Exec(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`, post.ID, reqTag.ID).Error
+		if len(tagsToBeDeleted) > 0 {
+			err = pr.detachTags(tx, req.ID, tagsToBeDeleted)
 			if err != nil {
 				return err
 			}
 		}
 
-		return nil
+		return attachTags(tx, req.ID, req.Tags)
 	})
 
 	if err != nil {
@@ -114,19 +349,172 @@ func (pr *PostRepository) UpdatePost(ctx context.Context, req model.Post, tagsTo
 	return nil
 }
 
+// UpdatePostStatus moves the post to req.Status and sets its publication
+// times, as long as req.Version is still the version of the post. Status
+// changes are not saved as revisions.
+func (pr *PostRepository) UpdatePostStatus(ctx context.Context, req model.Post) error {
+	return updatePost(pr.db.WithContext(ctx), req, "status", "published_at", "publish_at")
+}
+
+// PublishDuePosts publishes at most limit drafts scheduled before now and
+// returns how many were published. The drafts are locked with SKIP LOCKED, so
+// that concurrent calls, from other instances of the application, publish
+// other drafts instead of waiting for them.
+func (pr *PostRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error) {
+	var published int64
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{}
+		err := tx.Model(&model.Post{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND publish_at <= ?", model.PostStatusDraft, now).
+			Order("publish_at").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		res := tx.Model(&model.Post{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":       model.PostStatusPublished,
+				"published_at": gorm.Expr("COALESCE(published_at, ?)", now),
+				"publish_at":   nil,
+				"version":      gorm.Expr("version + 1"),
+				"updated_at":   gorm.Expr("now()"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		published = res.RowsAffected
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// DeletePost moves the post to the trash along with its comments and
+// attachments, as long as req.Version is still the version of the post. Its
+// tags are kept so that it can be restored.
 func (pr *PostRepository) DeletePost(ctx context.Context, req model.Post) error {
 	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		err := tx.Exec(`DELETE FROM post_tags WHERE post_id=$1;`, req.ID).Error
+		res := tx.Where("version = ?", req.Version).Delete(&model.Post{ID: req.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return model.ErrVersionConflict
+		}
+
+		err := tx.Where("post_id = ?", req.ID).Delete(&model.Comment{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("post_id = ?", req.ID).Delete(&model.Attachment{}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTrashedPosts lists the deleted posts, only the ones of the author and
+// the ones without author unless authorID is zero.
+func (pr *PostRepository) GetTrashedPosts(ctx context.Context, authorID, limit, offset int) ([]model.Post, int64, error) {
+	trashed := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("deleted_at IS NOT NULL")
+		if authorID != 0 {
+			db = db.Where("user_id = ? OR user_id IS NULL", authorID)
+		}
+		return db
+	}
+
+	var total int64
+	err := pr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Scopes(trashed).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.Post{}
+	err = pr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).Scopes(trashed).
+		Preload("Tags").Preload("User").Preload("ReactionCounts").
+		Order("deleted_at desc, id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (pr *PostRepository) GetTrashedPost(ctx context.Context, id int) (*model.Post, error) {
+	res := model.Post{}
+	err := pr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).
+		Where("deleted_at IS NOT NULL").
+		Preload("Tags").Preload("User").Preload("ReactionCounts").
+		First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// RestorePost moves the post back from the trash, with the tags, the comments
+// and the attachments it had.
+func (pr *PostRepository) RestorePost(ctx context.Context, id int) error {
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.Post{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": gorm.Expr("now()"),
+			}).Error
 		if err != nil {
 			return err
 		}
 
-		err = tx.WithContext(ctx).Delete(&req).Error
+		err = tx.Unscoped().Model(&model.Comment{}).
+			Where("post_id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil).Error
 		if err != nil {
 			return err
 		}
 
-		return nil
+		return tx.Unscoped().Model(&model.Attachment{}).
+			Where("post_id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PurgePost deletes the post for good, along with its tags, comments and
+// reactions.
+func (pr *PostRepository) PurgePost(ctx context.Context, id int) error {
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := pr.purgePosts(tx, []int{id})
+		return err
 	})
 
 	if err != nil {
@@ -135,3 +523,397 @@ func (pr *PostRepository) DeletePost(ctx context.Context, req model.Post) error
 
 	return nil
 }
+
+// GetUnrenderedPosts lists at most limit posts, deleted ones included, with
+// an id after afterID and a content whose html and metadata were never
+// stored, ordered by id. Their word count is null, as they were written before
+// the column existed, while every write stores it.
+func (pr *PostRepository) GetUnrenderedPosts(ctx context.Context, afterID, limit int) ([]model.Post, error) {
+	res := []model.Post{}
+	err := pr.db.WithContext(ctx).Unscoped().Model(&model.Post{}).
+		Where("id > ? AND word_count IS NULL", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateRenderedContent stores the html and the metadata rendered from the
+// content of the post. Its version is kept, as its content did not change.
+func (pr *PostRepository) UpdateRenderedContent(ctx context.Context, req model.Post) error {
+	return pr.db.WithContext(ctx).Unscoped().Model(&model.Post{ID: req.ID}).
+		UpdateColumns(map[string]any{
+			"content_html": req.ContentHTML,
+			"excerpt":      req.Excerpt,
+			"word_count":   req.WordCount,
+			"reading_time": req.ReadingTime,
+		}).Error
+}
+
+// PurgeTrashedPosts deletes for good the posts moved to the trash before
+// deletedBefore and returns how many were deleted.
+func (pr *PostRepository) PurgeTrashedPosts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := pr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int{}
+		err := tx.Unscoped().Model(&model.Post{}).Where("deleted_at < ?", deletedBefore).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		purged, err = pr.purgePosts(tx, ids)
+		return err
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// purgePosts deletes the posts for good along with their tags, revisions,
+// comments and reactions, and the tags left orphan when the cleanup is
+// enabled. The attachments are left for the attachment sweeper, which deletes
+// their blobs first.
+func (pr *PostRepository) purgePosts(tx *gorm.DB, ids []int) (int64, error) {
+	tagIDs := []int{}
+	if pr.cleanupOrphanTags {
+		err := tx.Table("post_tags").Distinct("tag_id").Where("post_id IN ?", ids).Pluck("tag_id", &tagIDs).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err := tx.Exec(`DELETE FROM post_tags WHERE post_id IN ?;`, ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Where("post_id IN ?", ids).Delete(&model.PostRevision{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Where("post_id IN ?", ids).Delete(&model.PostSlug{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Unscoped().Where("post_id IN ?", ids).Delete(&model.Comment{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Where("post_id IN ?", ids).Delete(&model.Reaction{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Where("post_id IN ?", ids).Delete(&model.PostReactionCount{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	res := tx.Unscoped().Delete(&model.Post{}, ids)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	if len(tagIDs) > 0 {
+		_, err = deleteOrphanTags(tx, tagIDs)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	PostsPurged.Add(res.RowsAffected)
+	return res.RowsAffected, nil
+}
+
+func (pr *PostRepository) GetPostRevisions(ctx context.Context, postID, limit, offset int) ([]model.PostRevision, int64, error) {
+	var total int64
+	err := pr.db.WithContext(ctx).Model(&model.PostRevision{}).Where("post_id = ?", postID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.PostRevision{}
+	err = pr.db.WithContext(ctx).Model(&model.PostRevision{}).
+		Where("post_id = ?", postID).
+		Order("revision desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (pr *PostRepository) GetPostRevision(ctx context.Context, postID, revision int) (*model.PostRevision, error) {
+	res := model.PostRevision{}
+	err := pr.db.WithContext(ctx).Where("post_id = ? AND revision = ?", postID, revision).First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// savePostRevision snapshots the post as a revision before it is updated. The
+// post is locked until the end of the transaction, so that concurrent updates
+// of the same version fail with model.ErrVersionConflict.
+func savePostRevision(tx *gorm.DB, req model.Post) error {
+	post := model.Post{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("version = ?", req.Version).
+		First(&post, req.ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.ErrVersionConflict
+		}
+		return err
+	}
+
+	tags := []model.Tag{}
+	err = tx.Table("tags").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Where("post_tags.post_id = ?", req.ID).
+		Order("tags.id").
+		Find(&tags).Error
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, len(tags))
+	for i, tag := range tags {
+		labels[i] = tag.Label
+	}
+
+	return tx.Create(&model.PostRevision{
+		PostID:        post.ID,
+		Revision:      post.Version,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Tags:          labels,
+	}).Error
+}
+
+// updatePost updates the columns of the post from req and bumps its version,
+// failing with model.ErrVersionConflict when req.Version is not the current
+// version of the post.
+func updatePost(tx *gorm.DB, req model.Post, columns ...string) error {
+	res := tx.Model(&model.Post{}).
+		Where("id = ? AND version = ?", req.ID, req.Version).
+		Select(append(columns[:len(columns):len(columns)], "version", "updated_at")).
+		Updates(map[string]any{
+			"title":          req.Title,
+			"slug":           req.Slug,
+			"content":        req.Content,
+			"content_format": req.ContentFormat,
+			"content_html":   req.ContentHTML,
+			"excerpt":        req.Excerpt,
+			"word_count":     req.WordCount,
+			"reading_time":   req.ReadingTime,
+			"status":         req.Status,
+			"published_at":   req.PublishedAt,
+			"publish_at":     req.PublishAt,
+			"version":        gorm.Expr("version + 1"),
+			"updated_at":     gorm.Expr("now()"),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// uniqueSlug makes base unique among the slugs and the old slugs of the posts
+// other than postID, by suffixing it with the lowest free number from 2. The
+// transactions making the same base unique wait for each other, so that they
+// never pick the same slug.
+func uniqueSlug(tx *gorm.DB, base string, postID int) (string, error) {
+	err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, base).Error
+	if err != nil {
+		return "", err
+	}
+
+	taken := []string{}
+	err = tx.Raw(`SELECT slug FROM posts WHERE id <> ? AND (slug = ? OR slug LIKE ?)
+		UNION SELECT slug FROM post_slugs WHERE post_id <> ? AND (slug = ? OR slug LIKE ?)`,
+		postID, base, base+"-%", postID, base, base+"-%").
+		Scan(&taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	slug := base
+	for n := 2; slices.Contains(taken, slug); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	return slug, nil
+}
+
+// changePostSlug moves the post to a slug made unique from base, unless its
+// slug already is made from base, and returns the slug of the post. The
+// previous slug is kept as an old slug of the post.
+func changePostSlug(tx *gorm.DB, postID int, base string) (string, error) {
+	current := sql.NullString{}
+	err := tx.Raw(`SELECT slug FROM posts WHERE id = ?`, postID).Scan(&current).Error
+	if err != nil {
+		return "", err
+	}
+
+	if current.Valid && slugFrom(current.String, base) {
+		return current.String, nil
+	}
+
+	slug, err := uniqueSlug(tx, base, postID)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Where("post_id = ? AND slug = ?", postID, slug).Delete(&model.PostSlug{}).Error
+	if err != nil {
+		return "", err
+	}
+
+	if current.Valid {
+		err = tx.Create(&model.PostSlug{PostID: postID, Slug: current.String}).Error
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return slug, nil
+}
+
+// slugFrom reports whether uniqueSlug could have made slug from base.
+func slugFrom(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+
+	n, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || n == "" {
+		return false
+	}
+
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectPostFields only loads the columns and the relations of the posts picked
+// by fields, the oldest comments of each post being loaded first along with
+// their authors.
+func selectPostFields(fields model.PostFields) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(fields.Columns) > 0 {
+			db = db.Select(fields.Columns)
+		}
+
+		if fields.Tags {
+			db = db.Preload("Tags")
+		}
+
+		if fields.Author {
+			db = db.Preload("User")
+		}
+
+		if fields.Reactions {
+			db = db.Preload("ReactionCounts")
+		}
+
+		if fields.Comments {
+			db = db.Preload("Comments", func(db *gorm.DB) *gorm.DB {
+				return db.
+					Where(`comments.id IN (SELECT oldest.id FROM comments AS oldest WHERE oldest.post_id = comments.post_id AND oldest.deleted_at IS NULL ORDER BY oldest.id LIMIT ?)`, fields.CommentsLimit).
+					Order("id")
+			}).Preload("Comments.User")
+		}
+
+		return db
+	}
+}
+
+// touchPost bumps the update time of the post, but not its version, for the
+// changes shown along with the post that are not part of it, such as its
+// reactions and its comments. The cached copies of the post are revalidated,
+// while its writes do not conflict with them.
+func touchPost(tx *gorm.DB, postID int) error {
+	return tx.Exec(`UPDATE posts SET updated_at = now() WHERE id = $1;`, postID).Error
+}
+
+// detachTags removes the tags from the post, along with the ones left orphan
+// when the cleanup is enabled.
+func (pr *PostRepository) detachTags(tx *gorm.DB, postID int, tagIDs []int) error {
+	err := tx.Exec(`DELETE FROM post_tags WHERE post_id=? and tag_id IN ?;`, postID, tagIDs).Error
+	if err != nil {
+		return err
+	}
+
+	if pr.cleanupOrphanTags && len(tagIDs) > 0 {
+		_, err = deleteOrphanTags(tx, tagIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachTags adds the tags to the post, creating the ones that do not exist
+// yet.
+func attachTags(tx *gorm.DB, postID int, tags []*model.Tag) error {
+	for _, tag := range tags {
+		reqTag, err := firstOrCreateTag(tx, tag)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`, postID, reqTag.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// firstOrCreateTag finds the tag with the same label as tag regardless of its
+// case, creating it when there is none. The tag found is locked until the
+// transaction ends, so that the removal of the orphan tags skips it until the
+// post is attached to it.
+func firstOrCreateTag(tx *gorm.DB, tag *model.Tag) (model.Tag, error) {
+	res := model.Tag{}
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("lower(label) = lower(?)", tag.Label).
+		Attrs(model.Tag{Label: tag.Label, Slug: tag.Slug}).
+		FirstOrCreate(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/elangreza14/assetfindr-test/model"
@@ -71,7 +72,7 @@ func TestPostRepositoryTestSuite(t *testing.T) {
 func (suite *TestPostRepositorySuite) TestPostRepository_GetPost() {
 	suite.Run("err", func() {
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "posts" WHERE "posts"."id" = $1 ORDER BY "posts"."id" LIMIT $2`)).
+			`SELECT * FROM "posts" WHERE "posts"."id" = $1 AND "posts"."deleted_at" IS NULL ORDER BY "posts"."id" LIMIT $2`)).
 			WithArgs(1, 1).WillReturnError(gorm.ErrInvalidData)
 
 		res, err := suite.postRepo.GetPost(context.Background(), 1)
@@ -82,10 +83,14 @@ func (suite *TestPostRepositorySuite) TestPostRepository_GetPost() {
 
 	suite.Run("success", func() {
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "posts" WHERE "posts"."id" = $1 ORDER BY "posts"."id" LIMIT $2`)).
+			`SELECT * FROM "posts" WHERE "posts"."id" = $1 AND "posts"."deleted_at" IS NULL ORDER BY "posts"."id" LIMIT $2`)).
 			WithArgs(1, 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "tags"}).AddRow(1, 1, 1, 1))
 
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
 			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
 			WithArgs(1).
@@ -103,22 +108,49 @@ func (suite *TestPostRepositorySuite) TestPostRepository_GetPost() {
 }
 
 func (suite *TestPostRepositorySuite) TestPostRepository_GetPosts() {
+	filter := model.PostFilter{Limit: 10, Offset: 10}
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts"`)).
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), filter)
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
 
 	suite.Run("err", func() {
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "posts" ORDER BY id desc`)).
+			`SELECT count(*) FROM "posts"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $1 OFFSET $2`)).
+			WithArgs(10, 10).
 			WillReturnError(gorm.ErrRecordNotFound)
 
-		res, err := suite.postRepo.GetPosts(context.Background())
+		res, total, err := suite.postRepo.GetPosts(context.Background(), filter)
 		suite.Error(err)
 		suite.Nil(res)
+		suite.Zero(total)
 	})
 
 	suite.Run("success", func() {
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "posts" ORDER BY id desc`)).
+			`SELECT count(*) FROM "posts"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $1 OFFSET $2`)).
+			WithArgs(10, 10).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "tags"}).AddRow(1, 1, 1, 1))
 
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
 			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
 			WithArgs(1).
@@ -129,30 +161,324 @@ func (suite *TestPostRepositorySuite) TestPostRepository_GetPosts() {
 			WithArgs(1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, 1))
 
-		res, err := suite.postRepo.GetPosts(context.Background())
+		res, total, err := suite.postRepo.GetPosts(context.Background(), filter)
 		suite.NoError(err)
 		suite.NotNil(res)
+		suite.Equal(int64(11), total)
+	})
+
+	suite.Run("success with fields", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT "id","title","user_id" FROM "posts" WHERE "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $1 OFFSET $2`)).
+			WithArgs(10, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "user_id"}).AddRow(1, "test", nil))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "comments" WHERE (comments.id IN (SELECT oldest.id FROM comments AS oldest WHERE oldest.post_id = comments.post_id AND oldest.deleted_at IS NULL ORDER BY oldest.id LIMIT $1)) AND "comments"."post_id" = $2 AND "comments"."deleted_at" IS NULL ORDER BY id`)).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "user_id", "body"}).AddRow(2, 1, 7, "hi"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "jane"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}).AddRow(1, 1))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "go"))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{
+			Limit:  10,
+			Offset: 10,
+			Fields: &model.PostFields{
+				Columns:       []string{"id", "title", "user_id"},
+				Author:        true,
+				Tags:          true,
+				Comments:      true,
+				CommentsLimit: 20,
+			},
+		})
+		suite.NoError(err)
+		suite.Equal(int64(11), total)
+		suite.Len(res, 1)
+		suite.Equal("go", res[0].Tags[0].Label)
+		suite.Equal("jane", res[0].Comments[0].User.Name)
+		suite.Nil(res[0].User)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostsWithCursor() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE id < $1 AND "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $2`)).
+			WithArgs(5, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(4, 1, 1))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{Limit: 10, Offset: 20, BeforeID: 5})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(int64(11), total)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostsByAuthor() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE posts.user_id = $1 AND "posts"."deleted_at" IS NULL`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE posts.user_id = $1 AND "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $2`)).
+			WithArgs(7, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "user_id"}).AddRow(1, 1, 1, 7))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(7, "jane", "jane@example.com"))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{Limit: 10, AuthorID: 7})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(int64(1), total)
+		suite.Equal("jane", res[0].User.Name)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostsSummary() {
+	suite.Run("success", func() {
+		updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT COUNT(*) AS total, MAX(posts.updated_at) AS last_updated_at FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1))`)).
+			WithArgs("go").
+			WillReturnRows(sqlmock.NewRows([]string{"total", "last_updated_at"}).AddRow(3, updatedAt))
+
+		res, err := suite.postRepo.GetPostsSummary(context.Background(), model.PostFilter{Tags: []string{"go"}})
+		suite.NoError(err)
+		suite.Equal(&model.PostsSummary{Total: 3, LastUpdatedAt: updatedAt}, res)
+	})
+
+	suite.Run("success with status", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT COUNT(*) AS total, MAX(posts.updated_at) AS last_updated_at FROM "posts" WHERE posts.status = $1 AND "posts"."deleted_at" IS NULL`)).
+			WithArgs(model.PostStatusPublished).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "last_updated_at"}).AddRow(0, nil))
+
+		res, err := suite.postRepo.GetPostsSummary(context.Background(), model.PostFilter{Status: model.PostStatusPublished})
+		suite.NoError(err)
+		suite.Equal(&model.PostsSummary{}, res)
+	})
+
+	suite.Run("success without posts", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT COUNT(*) AS total, MAX(posts.updated_at) AS last_updated_at FROM "posts"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "last_updated_at"}).AddRow(0, nil))
+
+		res, err := suite.postRepo.GetPostsSummary(context.Background(), model.PostFilter{})
+		suite.NoError(err)
+		suite.Equal(&model.PostsSummary{}, res)
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT COUNT(*) AS total, MAX(posts.updated_at) AS last_updated_at FROM "posts"`)).
+			WillReturnError(errors.New("err"))
+
+		_, err := suite.postRepo.GetPostsSummary(context.Background(), model.PostFilter{})
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostsWithTags() {
+	suite.Run("match any", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1,$2))`)).
+			WithArgs("go", "sql").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1,$2)) AND "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $3`)).
+			WithArgs("go", "sql", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(1, 1, 1))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{Limit: 10, Tags: []string{"go", "sql"}})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(int64(1), total)
+	})
+
+	suite.Run("match all", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1,$2) GROUP BY "post_tags"."post_id" HAVING COUNT(DISTINCT tags.id) = $3)`)).
+			WithArgs("go", "sql", 2).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1,$2) GROUP BY "post_tags"."post_id" HAVING COUNT(DISTINCT tags.id) = $3) AND "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $4`)).
+			WithArgs("go", "sql", 2, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{Limit: 10, Tags: []string{"go", "sql"}, MatchAllTags: true})
+		suite.NoError(err)
+		suite.Empty(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE posts.id IN (SELECT post_tags.post_id FROM "post_tags" JOIN tags ON tags.id = post_tags.tag_id WHERE lower(tags.label) IN ($1))`)).
+			WithArgs("go").
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.postRepo.GetPosts(context.Background(), model.PostFilter{Limit: 10, Tags: []string{"go"}})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_SearchPosts() {
+	countSQL := regexp.QuoteMeta(`SELECT count(*) FROM "posts" WHERE posts.status = $1 AND posts.search_vector @@ websearch_to_tsquery('english', $2)`)
+	searchSQL := regexp.QuoteMeta(`SELECT posts.id,`) + `.+` +
+		regexp.QuoteMeta(`FROM "posts" WHERE posts.status = $4 AND posts.search_vector @@ websearch_to_tsquery('english', $5) AND "posts"."deleted_at" IS NULL ORDER BY rank desc, posts.id desc LIMIT $6`)
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(model.PostStatusPublished, "golang").
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.postRepo.SearchPosts(context.Background(), "golang", 10, 0)
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("err search", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(model.PostStatusPublished, "golang").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		suite.mock.ExpectQuery(searchSQL).
+			WithArgs("golang", "golang", "StartSel=\"\x02\", StopSel=\"\x03\", MaxFragments=2", model.PostStatusPublished, "golang", 10).
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.postRepo.SearchPosts(context.Background(), "golang", 10, 0)
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("no match", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(model.PostStatusPublished, "golang").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectQuery(searchSQL).
+			WithArgs("golang", "golang", "StartSel=\"\x02\", StopSel=\"\x03\", MaxFragments=2", model.PostStatusPublished, "golang", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "snippet"}))
+
+		res, total, err := suite.postRepo.SearchPosts(context.Background(), "golang", 10, 0)
+		suite.NoError(err)
+		suite.Empty(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(countSQL).
+			WithArgs(model.PostStatusPublished, "golang").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		suite.mock.ExpectQuery(searchSQL).
+			WithArgs("golang", "golang", "StartSel=\"\x02\", StopSel=\"\x03\", MaxFragments=2", model.PostStatusPublished, "golang", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "snippet"}).
+				AddRow(2, 0.9, "\x02golang\x03 rocks").
+				AddRow(1, 0.1, "about \x02golang\x03"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE "posts"."id" IN ($1,$2)`)).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(1, "a", "about golang").AddRow(2, "b", "golang rocks"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		res, total, err := suite.postRepo.SearchPosts(context.Background(), "golang", 10, 0)
+		suite.NoError(err)
+		suite.Equal(int64(2), total)
+		suite.Len(res, 2)
+		suite.Equal(2, res[0].Post.ID)
+		suite.Equal(0.9, res[0].Rank)
+		suite.Equal("\x02golang\x03 rocks", res[0].Snippet)
+		suite.Equal(1, res[1].Post.ID)
 	})
 }
 
 func (suite *TestPostRepositorySuite) TestPostRepository_CreatePost() {
 	testReq := model.Post{
-		Title:   "test",
-		Content: "test",
+		Title:         "test",
+		Slug:          "test",
+		Content:       "test",
+		ContentFormat: model.ContentFormatMarkdown,
+		ContentHTML:   "<p>test</p>\n",
+		Excerpt:       "test",
+		WordCount:     1,
+		ReadingTime:   1,
 		Tags: []*model.Tag{{
 			ID:    1,
 			Label: "test",
 		}},
+		Status: model.PostStatusDraft,
 	}
 
 	suite.Run("success", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectUniqueSlug("test", 0, sqlmock.NewRows([]string{"slug"}).AddRow("test").AddRow("test-1"))
 		suite.mock.ExpectQuery(
-			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content") VALUES ($1,$2) RETURNING "id"`)).
-			WithArgs("test", "test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content","content_format","content_html","excerpt","word_count","reading_time","user_id","status","published_at","publish_at","version","deleted_at","slug") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "slug","created_at","updated_at","id"`)).
+			WithArgs("test", "test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", 1, 1, nil, model.PostStatusDraft, nil, nil, 1, nil, "test-2").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test", 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test"))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
@@ -164,14 +490,48 @@ func (suite *TestPostRepositorySuite) TestPostRepository_CreatePost() {
 		suite.NoError(err)
 	})
 
+	suite.Run("success creating tag", func() {
+
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(
+			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content","content_format","content_html","excerpt","word_count","reading_time","user_id","status","published_at","publish_at","version","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "slug","created_at","updated_at","id"`)).
+			WithArgs("test", "test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", 1, 1, nil, model.PostStatusDraft, nil, nil, 1, nil).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
+			WithArgs("test", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "slug"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`INSERT INTO "tags" ("label","slug") VALUES ($1,$2) RETURNING "id"`)).
+			WithArgs("test", "test").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.CreatePost(context.Background(), model.Post{
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatMarkdown,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "test", Slug: "test"}},
+			Status:        model.PostStatusDraft,
+		})
+		suite.NoError(err)
+	})
+
 	suite.Run("err insert tags", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectUniqueSlug("test", 0, sqlmock.NewRows([]string{"slug"}))
 		suite.mock.ExpectQuery(
-			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content") VALUES ($1,$2) RETURNING "id"`)).
-			WithArgs("test", "test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content","content_format","content_html","excerpt","word_count","reading_time","user_id","status","published_at","publish_at","version","deleted_at","slug") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "slug","created_at","updated_at","id"`)).
+			WithArgs("test", "test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", 1, 1, nil, model.PostStatusDraft, nil, nil, 1, nil, "test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test", 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test"))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
@@ -186,9 +546,10 @@ func (suite *TestPostRepositorySuite) TestPostRepository_CreatePost() {
 	suite.Run("err insert post", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectUniqueSlug("test", 0, sqlmock.NewRows([]string{"slug"}))
 		suite.mock.ExpectQuery(
-			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content") VALUES ($1,$2) RETURNING "id"`)).
-			WithArgs("test", "test").WillReturnError(errors.New("err"))
+			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content","content_format","content_html","excerpt","word_count","reading_time","user_id","status","published_at","publish_at","version","deleted_at","slug") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "slug","created_at","updated_at","id"`)).
+			WithArgs("test", "test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", 1, 1, nil, model.PostStatusDraft, nil, nil, 1, nil, "test").WillReturnError(errors.New("err"))
 
 		suite.mock.ExpectRollback()
 
@@ -199,11 +560,12 @@ func (suite *TestPostRepositorySuite) TestPostRepository_CreatePost() {
 	suite.Run("err get tags", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectUniqueSlug("test", 0, sqlmock.NewRows([]string{"slug"}))
 		suite.mock.ExpectQuery(
-			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content") VALUES ($1,$2) RETURNING "id"`)).
-			WithArgs("test", "test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			regexp.QuoteMeta(`INSERT INTO "posts" ("title","content","content_format","content_html","excerpt","word_count","reading_time","user_id","status","published_at","publish_at","version","deleted_at","slug") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "slug","created_at","updated_at","id"`)).
+			WithArgs("test", "test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", 1, 1, nil, model.PostStatusDraft, nil, nil, 1, nil, "test").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test", 1).
 			WillReturnError(errors.New("err"))
 
@@ -214,28 +576,67 @@ func (suite *TestPostRepositorySuite) TestPostRepository_CreatePost() {
 	})
 }
 
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostBySlug() {
+	query := regexp.QuoteMeta(`SELECT * FROM "posts" WHERE (slug = $1 OR id IN (SELECT "post_id" FROM "post_slugs" WHERE slug = $2)) AND "posts"."deleted_at" IS NULL ORDER BY "posts"."id" LIMIT $3`)
+
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(query).
+			WithArgs("test", "test", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		res, err := suite.postRepo.GetPostBySlug(context.Background(), "test")
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(query).
+			WithArgs("old-test", "old-test", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug"}).AddRow(1, "test", "test"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		res, err := suite.postRepo.GetPostBySlug(context.Background(), "old-test")
+		suite.NoError(err)
+		suite.Equal("test", res.Slug)
+	})
+}
+
 func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 	testReq := model.Post{
-		ID:      1,
-		Title:   "test",
-		Content: "test",
+		ID:            1,
+		Title:         "test",
+		Content:       "test",
+		ContentFormat: model.ContentFormatMarkdown,
+		ContentHTML:   "<p>test</p>\n",
+		Excerpt:       "test",
+		WordCount:     1,
+		ReadingTime:   1,
 		Tags: []*model.Tag{{
 			ID:    1,
 			Label: "test 1",
 		}},
+		Version: 3,
 	}
+	updateSQL := regexp.QuoteMeta(`UPDATE "posts" SET "content"=$1,"content_format"=$2,"content_html"=$3,"excerpt"=$4,"publish_at"=$5,"reading_time"=$6,"title"=$7,"updated_at"=now(),"version"=version + 1,"word_count"=$8 WHERE (id = $9 AND version = $10) AND "posts"."deleted_at" IS NULL`)
 
 	suite.Run("success", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
 			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
-		updUserSQL := "UPDATE \"posts\" SET .+"
-		suite.mock.ExpectExec(updUserSQL).
-			WithArgs("test", "test", 1).
-			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test 1", 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test 1"))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
@@ -247,17 +648,98 @@ func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 		suite.NoError(err)
 	})
 
-	suite.Run("err insert into post tags", func() {
+	slugUpdateSQL := regexp.QuoteMeta(`UPDATE "posts" SET "content"=$1,"content_format"=$2,"content_html"=$3,"excerpt"=$4,"publish_at"=$5,"reading_time"=$6,"slug"=$7,"title"=$8,"updated_at"=now(),"version"=version + 1,"word_count"=$9 WHERE (id = $10 AND version = $11) AND "posts"."deleted_at" IS NULL`)
+	slugReq := testReq
+	slugReq.Slug = "test"
+
+	suite.Run("success changing the slug", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM posts WHERE id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("old-title"))
+		suite.expectUniqueSlug("test", 1, sqlmock.NewRows([]string{"slug"}).AddRow("test"))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_slugs" WHERE post_id = $1 AND slug = $2`)).
+			WithArgs(1, "test-2").
+			WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "post_slugs" ("post_id","slug") VALUES ($1,$2) RETURNING "created_at","id"`)).
+			WithArgs(1, "old-title").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(time.Now(), 1))
+		suite.mock.ExpectExec(slugUpdateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test-2", "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
 			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
-		updUserSQL := "UPDATE \"posts\" SET .+"
-		suite.mock.ExpectExec(updUserSQL).
-			WithArgs("test", "test", 1).
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
+			WithArgs("test 1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test 1"))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
+			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
+
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.UpdatePost(context.Background(), slugReq, 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("err version conflict keeping the slug", func() {
+
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM posts WHERE id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("test-3"))
+		suite.mock.ExpectExec(slugUpdateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test-3", "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.UpdatePost(context.Background(), slugReq, 1)
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
+
+	suite.Run("err version conflict", func() {
+
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.UpdatePost(context.Background(), testReq, 1)
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
+
+	suite.Run("err version conflict on locking the post", func() {
+
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE version = $1 AND "posts"."id" = $2 AND "posts"."deleted_at" IS NULL ORDER BY "posts"."id" LIMIT $3 FOR UPDATE`)).
+			WithArgs(3, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.UpdatePost(context.Background(), testReq, 1)
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
+
+	suite.Run("err insert into post tags", func() {
+
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test 1", 1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test 1"))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
@@ -272,14 +754,14 @@ func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 	suite.Run("err first or create", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
 			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
-		updUserSQL := "UPDATE \"posts\" SET .+"
-		suite.mock.ExpectExec(updUserSQL).
-			WithArgs("test", "test", 1).
-			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectQuery(regexp.QuoteMeta(
-			`SELECT * FROM "tags" WHERE "tags"."label" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
 			WithArgs("test 1", 1).WillReturnError(errors.New("err"))
 
 		suite.mock.ExpectRollback()
@@ -288,14 +770,36 @@ func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 		suite.Error(err)
 	})
 
+	suite.Run("err delete post tags", func() {
+
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 1).WillReturnError(errors.New("err"))
+
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.UpdatePost(context.Background(), testReq, 1)
+		suite.Error(err)
+	})
+
 	suite.Run("err first or create 2", func() {
 
 		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
 			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
-		updUserSQL := "UPDATE \"posts\" SET .+"
-		suite.mock.ExpectExec(updUserSQL).
-			WithArgs("test", "test", 1).
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
+			WithArgs("test 1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "tags"`)).
 			WillReturnError(errors.New("err"))
 
 		suite.mock.ExpectRollback()
@@ -304,11 +808,13 @@ func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 		suite.Error(err)
 	})
 
-	suite.Run("err delete post tags", func() {
+	suite.Run("err update", func() {
 
 		suite.mock.ExpectBegin()
-		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
-			WithArgs(1, 1).WillReturnError(errors.New("err"))
+		suite.expectSavePostRevision(1, 3)
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs("test", model.ContentFormatMarkdown, "<p>test</p>\n", "test", nil, 1, "test", 1, 1, 3).
+			WillReturnError(errors.New("err"))
 
 		suite.mock.ExpectRollback()
 
@@ -317,32 +823,290 @@ func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePost() {
 	})
 }
 
-func (suite *TestPostRepositorySuite) TestPostRepository_DeletePost() {
+func (suite *TestPostRepositorySuite) TestPostRepository_PatchPost() {
+	suite.Run("success only title", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 2)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "posts" SET "title"=$1,"updated_at"=now(),"version"=version + 1 WHERE (id = $2 AND version = $3) AND "posts"."deleted_at" IS NULL`)).
+			WithArgs("new title", 1, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.PatchPost(context.Background(), model.Post{ID: 1, Title: "new title", Version: 2}, []string{"title"})
+		suite.NoError(err)
+	})
+
+	suite.Run("success only tags", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 2)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "posts" SET "updated_at"=now(),"version"=version + 1 WHERE (id = $1 AND version = $2) AND "posts"."deleted_at" IS NULL`)).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2 FOR SHARE`)).
+			WithArgs("test 1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test 1"))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING`)).
+			WithArgs(1, 1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.PatchPost(context.Background(), model.Post{
+			ID:      1,
+			Tags:    []*model.Tag{{Label: "test 1"}},
+			Version: 2,
+		}, nil, 2)
+		suite.NoError(err)
+	})
+
+	suite.Run("err version conflict", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 2)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "posts" SET "content"=$1,"title"=$2,"updated_at"=now(),"version"=version + 1 WHERE (id = $3 AND version = $4) AND "posts"."deleted_at" IS NULL`)).
+			WithArgs("new content", "new title", 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.PatchPost(context.Background(), model.Post{ID: 1, Title: "new title", Content: "new content", Version: 2}, []string{"title", "content"})
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
+
+	suite.Run("err detach tags", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 2)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "posts" SET "updated_at"=now(),"version"=version + 1 WHERE (id = $1 AND version = $2) AND "posts"."deleted_at" IS NULL`)).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 2).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.PatchPost(context.Background(), model.Post{ID: 1, Version: 2}, nil, 2)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostRevisions() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "post_revisions" WHERE post_id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_revisions" WHERE post_id = $1 ORDER BY revision desc LIMIT $2`)).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "tags"}).
+				AddRow(2, 1, 2, "test 2", "test 2", `["a","b"]`).
+				AddRow(1, 1, 1, "test 1", "test 1", `["a"]`))
+
+		res, total, err := suite.postRepo.GetPostRevisions(context.Background(), 1, 10, 0)
+		suite.NoError(err)
+		suite.Equal(int64(2), total)
+		suite.Len(res, 2)
+		suite.Equal([]string{"a", "b"}, res[0].Tags)
+	})
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "post_revisions" WHERE post_id = $1`)).
+			WithArgs(1).
+			WillReturnError(errors.New("err"))
+
+		_, _, err := suite.postRepo.GetPostRevisions(context.Background(), 1, 10, 0)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetPostRevision() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_revisions" WHERE post_id = $1 AND revision = $2 ORDER BY "post_revisions"."id" LIMIT $3`)).
+			WithArgs(1, 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "tags"}).
+				AddRow(2, 1, 2, "test 2", "test 2", `["a","b"]`))
+
+		res, err := suite.postRepo.GetPostRevision(context.Background(), 1, 2)
+		suite.NoError(err)
+		suite.Equal(2, res.Revision)
+	})
+
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_revisions" WHERE post_id = $1 AND revision = $2 ORDER BY "post_revisions"."id" LIMIT $3`)).
+			WithArgs(1, 3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := suite.postRepo.GetPostRevision(context.Background(), 1, 3)
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+// expectSavePostRevision expects the post to be locked and saved as a
+// revision, with the tag "test 1".
+func (suite *TestPostRepositorySuite) expectUniqueSlug(base string, postID int, taken *sqlmock.Rows) {
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext($1))`)).
+		WithArgs(base).
+		WillReturnResult(driver.ResultNoRows)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT slug FROM posts WHERE id <> $1 AND (slug = $2 OR slug LIKE $3)`)).
+		WithArgs(postID, base, base+"-%", postID, base, base+"-%").
+		WillReturnRows(taken)
+}
+
+func (suite *TestPostRepositorySuite) expectSavePostRevision(id, version int) {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "posts" WHERE version = $1 AND "posts"."id" = $2 AND "posts"."deleted_at" IS NULL ORDER BY "posts"."id" LIMIT $3 FOR UPDATE`)).
+		WithArgs(version, id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "content_format", "version"}).AddRow(id, "old title", "old content", "plain", version))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "tags"."id","tags"."label","tags"."slug" FROM "tags" JOIN post_tags ON post_tags.tag_id = tags.id WHERE post_tags.post_id = $1 ORDER BY tags.id`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "test 1"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "post_revisions" ("post_id","revision","title","content","content_format","tags") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "created_at","id"`)).
+		WithArgs(id, version, "old title", "old content", model.ContentFormatPlain, `["test 1"]`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "id"}).AddRow(time.Now(), 1))
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_UpdatePostStatus() {
+	publishedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
 	testReq := model.Post{
-		ID: 1,
+		ID:          1,
+		Status:      model.PostStatusPublished,
+		PublishedAt: &publishedAt,
+		Version:     2,
 	}
+	updateSQL := regexp.QuoteMeta(`UPDATE "posts" SET "publish_at"=$1,"published_at"=$2,"status"=$3,"updated_at"=now(),"version"=version + 1 WHERE (id = $4 AND version = $5) AND "posts"."deleted_at" IS NULL`)
 
 	suite.Run("success", func() {
-
 		suite.mock.ExpectBegin()
-		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1;`)).
-			WithArgs(1).WillReturnResult(driver.ResultNoRows)
-		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "posts" WHERE "posts"."id" = $1`)).
-			WithArgs(1).
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs(nil, &publishedAt, model.PostStatusPublished, 1, 2).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.UpdatePostStatus(context.Background(), testReq)
+		suite.NoError(err)
+	})
+
+	suite.Run("err version conflict", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(updateSQL).
+			WithArgs(nil, &publishedAt, model.PostStatusPublished, 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.UpdatePostStatus(context.Background(), testReq)
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_PublishDuePosts() {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	selectSQL := regexp.QuoteMeta(`SELECT "id" FROM "posts" WHERE (status = $1 AND publish_at <= $2) AND "posts"."deleted_at" IS NULL ORDER BY publish_at LIMIT $3 FOR UPDATE SKIP LOCKED`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(model.PostStatusDraft, now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "posts" SET "publish_at"=$1,"published_at"=COALESCE(published_at, $2),"status"=$3,"updated_at"=now(),"version"=version + 1 WHERE id IN ($4,$5) AND "posts"."deleted_at" IS NULL`)).
+			WithArgs(nil, now, model.PostStatusPublished, 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectCommit()
+
+		published, err := suite.postRepo.PublishDuePosts(context.Background(), now, 100)
+		suite.NoError(err)
+		suite.Equal(int64(2), published)
+	})
+
+	suite.Run("success nothing due", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(model.PostStatusDraft, now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		suite.mock.ExpectCommit()
+
+		published, err := suite.postRepo.PublishDuePosts(context.Background(), now, 100)
+		suite.NoError(err)
+		suite.Equal(int64(0), published)
+	})
+
+	suite.Run("err select", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(model.PostStatusDraft, now, 100).
+			WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		_, err := suite.postRepo.PublishDuePosts(context.Background(), now, 100)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_DeletePost() {
+	testReq := model.Post{
+		ID:      1,
+		Version: 2,
+	}
+	deleteSQL := regexp.QuoteMeta(`UPDATE "posts" SET "deleted_at"=$1 WHERE version = $2 AND "posts"."id" = $3 AND "posts"."deleted_at" IS NULL`)
+	deleteCommentsSQL := regexp.QuoteMeta(`UPDATE "comments" SET "deleted_at"=$1 WHERE post_id = $2 AND "comments"."deleted_at" IS NULL`)
+	deleteAttachmentsSQL := regexp.QuoteMeta(`UPDATE "attachments" SET "deleted_at"=$1 WHERE post_id = $2 AND "attachments"."deleted_at" IS NULL`)
 
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(sqlmock.AnyArg(), 2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(deleteCommentsSQL).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		suite.mock.ExpectExec(deleteAttachmentsSQL).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
 		suite.mock.ExpectCommit()
 
 		err := suite.postRepo.DeletePost(context.Background(), testReq)
 		suite.NoError(err)
 	})
 
-	suite.Run("err delete", func() {
+	suite.Run("err version conflict", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(sqlmock.AnyArg(), 2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.DeletePost(context.Background(), testReq)
+		suite.ErrorIs(err, model.ErrVersionConflict)
+	})
 
+	suite.Run("err delete comments", func() {
 		suite.mock.ExpectBegin()
-		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1;`)).
-			WithArgs(1).WillReturnError(errors.New("err db"))
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(sqlmock.AnyArg(), 2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(deleteCommentsSQL).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnError(errors.New("err db"))
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.DeletePost(context.Background(), testReq)
+		suite.Error(err)
+	})
 
+	suite.Run("err delete attachments", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(sqlmock.AnyArg(), 2, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(deleteCommentsSQL).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		suite.mock.ExpectExec(deleteAttachmentsSQL).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnError(errors.New("err db"))
 		suite.mock.ExpectRollback()
 
 		err := suite.postRepo.DeletePost(context.Background(), testReq)
@@ -350,17 +1114,303 @@ func (suite *TestPostRepositorySuite) TestPostRepository_DeletePost() {
 	})
 
 	suite.Run("err delete", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(sqlmock.AnyArg(), 2, 1).
+			WillReturnError(errors.New("err db"))
+		suite.mock.ExpectRollback()
+
+		err := suite.postRepo.DeletePost(context.Background(), testReq)
+		suite.Error(err)
+	})
+}
 
+func (suite *TestPostRepositorySuite) TestPostRepository_GetTrashedPosts() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE deleted_at IS NOT NULL`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE deleted_at IS NOT NULL ORDER BY deleted_at desc, id desc LIMIT $1 OFFSET $2`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "deleted_at"}).AddRow(4, "test", "test", time.Now()))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+		res, total, err := suite.postRepo.GetTrashedPosts(context.Background(), 0, 1, 2)
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.True(res[0].DeletedAt.Valid)
+		suite.Equal(int64(3), total)
+	})
+
+	suite.Run("success of an author", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE deleted_at IS NOT NULL AND (user_id = $1 OR user_id IS NULL)`)).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE deleted_at IS NOT NULL AND (user_id = $1 OR user_id IS NULL) ORDER BY deleted_at desc, id desc LIMIT $2 OFFSET $3`)).
+			WithArgs(7, 1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "deleted_at"}))
+
+		res, total, err := suite.postRepo.GetTrashedPosts(context.Background(), 7, 1, 2)
+		suite.NoError(err)
+		suite.Empty(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE deleted_at IS NOT NULL`)).
+			WillReturnError(errors.New("err"))
+
+		_, _, err := suite.postRepo.GetTrashedPosts(context.Background(), 0, 1, 2)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetUnrenderedPosts() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE id > $1 AND word_count IS NULL ORDER BY id LIMIT $2`)).
+			WithArgs(4, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "content"}).AddRow(5, "one two"))
+
+		res, err := suite.postRepo.GetUnrenderedPosts(context.Background(), 4, 100)
+		suite.NoError(err)
+		suite.Equal([]model.Post{{ID: 5, Content: "one two"}}, res)
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE id > $1 AND word_count IS NULL ORDER BY id LIMIT $2`)).
+			WithArgs(0, 100).
+			WillReturnError(errors.New("err"))
+
+		res, err := suite.postRepo.GetUnrenderedPosts(context.Background(), 0, 100)
+		suite.Error(err)
+		suite.Nil(res)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_UpdateRenderedContent() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "posts" SET "content_html"=$1,"excerpt"=$2,"reading_time"=$3,"word_count"=$4 WHERE "id" = $5`)).
+			WithArgs("<p>one two</p>\n", "one two", 1, 2, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.UpdateRenderedContent(context.Background(), model.Post{
+			ID:          5,
+			ContentHTML: "<p>one two</p>\n",
+			Excerpt:     "one two",
+			WordCount:   2,
+			ReadingTime: 1,
+		})
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_GetTrashedPost() {
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE deleted_at IS NOT NULL AND "posts"."id" = $1 ORDER BY "posts"."id" LIMIT $2`)).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := suite.postRepo.GetTrashedPost(context.Background(), 1)
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_RestorePost() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "posts" SET "deleted_at"=$1,"updated_at"=now(),"version"=version + 1 WHERE id = $2 AND deleted_at IS NOT NULL`)).
+			WithArgs(nil, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "comments" SET "deleted_at"=$1 WHERE post_id = $2 AND deleted_at IS NOT NULL`)).
+			WithArgs(nil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "attachments" SET "deleted_at"=$1 WHERE post_id = $2 AND deleted_at IS NOT NULL`)).
+			WithArgs(nil, 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectCommit()
+
+		err := suite.postRepo.RestorePost(context.Background(), 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_PurgePost() {
+	suite.Run("success", func() {
 		suite.mock.ExpectBegin()
-		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1;`)).
-			WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id IN ($1);`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_revisions" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_slugs" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comments" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "reactions" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_reaction_counts" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
 		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "posts" WHERE "posts"."id" = $1`)).
 			WithArgs(1).
-			WillReturnError(errors.New("err db"))
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		purged := PostsPurged.Value()
+		err := suite.postRepo.PurgePost(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(purged+1, PostsPurged.Value())
+	})
 
+	suite.Run("err delete post tags", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id IN ($1);`)).
+			WithArgs(1).WillReturnError(errors.New("err"))
 		suite.mock.ExpectRollback()
 
-		err := suite.postRepo.DeletePost(context.Background(), testReq)
+		err := suite.postRepo.PurgePost(context.Background(), 1)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostRepositorySuite) TestPostRepository_PurgeTrashedPosts() {
+	deletedBefore := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	selectSQL := regexp.QuoteMeta(`SELECT "id" FROM "posts" WHERE deleted_at < $1`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(deletedBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id IN ($1,$2);`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_revisions" WHERE post_id IN ($1,$2)`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_slugs" WHERE post_id IN ($1,$2)`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comments" WHERE post_id IN ($1,$2)`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "reactions" WHERE post_id IN ($1,$2)`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_reaction_counts" WHERE post_id IN ($1,$2)`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "posts" WHERE "posts"."id" IN ($1,$2)`)).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectCommit()
+
+		purged, err := suite.postRepo.PurgeTrashedPosts(context.Background(), deletedBefore)
+		suite.NoError(err)
+		suite.Equal(int64(2), purged)
+	})
+
+	suite.Run("success nothing to purge", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(deletedBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		suite.mock.ExpectCommit()
+
+		purged, err := suite.postRepo.PurgeTrashedPosts(context.Background(), deletedBefore)
+		suite.NoError(err)
+		suite.Equal(int64(0), purged)
+	})
+
+	suite.Run("err select", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(selectSQL).
+			WithArgs(deletedBefore).
+			WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		_, err := suite.postRepo.PurgeTrashedPosts(context.Background(), deletedBefore)
 		suite.Error(err)
 	})
 }
+
+func (suite *TestPostRepositorySuite) TestPostRepository_OrphanTagCleanup() {
+	postRepo := NewPostRepository(suite.gormDB, WithOrphanTagCleanup())
+	orphanSQL := regexp.QuoteMeta(`DELETE FROM "tags" WHERE id IN (SELECT "id" FROM "tags" WHERE NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id) AND tags.id IN ($1) FOR UPDATE SKIP LOCKED)`)
+
+	suite.Run("update post", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 1)
+		suite.mock.ExpectExec("UPDATE \"posts\" SET .+").
+			WithArgs("test", "", "", "", nil, 0, "test", 0, 1, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(orphanSQL).
+			WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		removed := OrphanTagsRemoved.Value()
+		err := postRepo.UpdatePost(context.Background(), model.Post{ID: 1, Title: "test", Content: "test", Version: 1}, 2)
+		suite.NoError(err)
+		suite.Equal(removed+1, OrphanTagsRemoved.Value())
+	})
+
+	suite.Run("err update post", func() {
+		suite.mock.ExpectBegin()
+		suite.expectSavePostRevision(1, 1)
+		suite.mock.ExpectExec("UPDATE \"posts\" SET .+").
+			WithArgs("test", "", "", "", nil, 0, "test", 0, 1, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id=$1 and tag_id IN ($2);`)).
+			WithArgs(1, 2).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(orphanSQL).
+			WithArgs(2).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := postRepo.UpdatePost(context.Background(), model.Post{ID: 1, Title: "test", Content: "test", Version: 1}, 2)
+		suite.Error(err)
+	})
+
+	suite.Run("purge post", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT tag_id FROM "post_tags" WHERE post_id IN ($1)`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(3))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE post_id IN ($1);`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_revisions" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_slugs" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comments" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "reactions" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "post_reaction_counts" WHERE post_id IN ($1)`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "posts" WHERE "posts"."id" = $1`)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectExec(orphanSQL).
+			WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		removed := OrphanTagsRemoved.Value()
+		err := postRepo.PurgePost(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(removed+1, OrphanTagsRemoved.Value())
+	})
+}
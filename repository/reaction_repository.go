@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) *ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// AddReaction adds the reaction and counts it in the same transaction, which
+// touches the post. Adding a reaction already there changes nothing.
+func (rr *ReactionRepository) AddReaction(ctx context.Context, req model.Reaction) error {
+	err := rr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reaction := model.Reaction{
+			PostID: req.PostID,
+			UserID: req.UserID,
+			Kind:   req.Kind,
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "post_id"}, {Name: "kind"}},
+			DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("post_reaction_counts.count + 1")}),
+		}).Create(&model.PostReactionCount{PostID: req.PostID, Kind: req.Kind, Count: 1}).Error
+		if err != nil {
+			return err
+		}
+
+		return touchPost(tx, req.PostID)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveReaction removes the reaction and uncounts it in the same
+// transaction, which touches the post. Removing a reaction not there changes
+// nothing.
+func (rr *ReactionRepository) RemoveReaction(ctx context.Context, req model.Reaction) error {
+	err := rr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("post_id = ? AND user_id = ? AND kind = ?", req.PostID, req.UserID, req.Kind).Delete(&model.Reaction{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		err := tx.Model(&model.PostReactionCount{}).
+			Where("post_id = ? AND kind = ?", req.PostID, req.Kind).
+			Update("count", gorm.Expr("count - 1")).Error
+		if err != nil {
+			return err
+		}
+
+		return touchPost(tx, req.PostID)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
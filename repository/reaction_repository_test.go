@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+var touchPostSQL = regexp.QuoteMeta(`UPDATE posts SET updated_at = now() WHERE id = $1;`)
+
+type TestReactionRepositorySuite struct {
+	suite.Suite
+
+	sqlDB        *sql.DB
+	gormDB       *gorm.DB
+	mock         sqlmock.Sqlmock
+	reactionRepo *ReactionRepository
+}
+
+func (suite *TestReactionRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.reactionRepo = NewReactionRepository(gormDB)
+}
+
+func (suite *TestReactionRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestReactionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestReactionRepositorySuite))
+}
+
+func (suite *TestReactionRepositorySuite) TestReactionRepository_AddReaction() {
+	reaction := model.Reaction{PostID: 1, UserID: 7, Kind: model.ReactionLike}
+	insertSQL := regexp.QuoteMeta(
+		`INSERT INTO "reactions" ("post_id","user_id","kind") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING RETURNING "created_at"`)
+	countSQL := regexp.QuoteMeta(
+		`INSERT INTO "post_reaction_counts" ("post_id","kind","count") VALUES ($1,$2,$3) ON CONFLICT ("post_id","kind") DO UPDATE SET "count"=post_reaction_counts.count + 1`)
+
+	suite.Run("success touches the post", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(insertSQL).
+			WithArgs(1, 7, "like").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(nil))
+		suite.mock.ExpectExec(countSQL).
+			WithArgs(1, "like", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectExec(touchPostSQL).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.reactionRepo.AddReaction(context.Background(), reaction)
+		suite.NoError(err)
+	})
+
+	suite.Run("success already added", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(insertSQL).
+			WithArgs(1, 7, "like").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+		suite.mock.ExpectCommit()
+
+		err := suite.reactionRepo.AddReaction(context.Background(), reaction)
+		suite.NoError(err)
+	})
+
+	suite.Run("err count", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(insertSQL).
+			WithArgs(1, 7, "like").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(nil))
+		suite.mock.ExpectExec(countSQL).
+			WithArgs(1, "like", 1).
+			WillReturnError(errors.New("err db"))
+		suite.mock.ExpectRollback()
+
+		err := suite.reactionRepo.AddReaction(context.Background(), reaction)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestReactionRepositorySuite) TestReactionRepository_RemoveReaction() {
+	reaction := model.Reaction{PostID: 1, UserID: 7, Kind: model.ReactionLike}
+	deleteSQL := regexp.QuoteMeta(
+		`DELETE FROM "reactions" WHERE post_id = $1 AND user_id = $2 AND kind = $3`)
+
+	suite.Run("success touches the post", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(1, 7, "like").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "post_reaction_counts" SET "count"=count - 1 WHERE post_id = $1 AND kind = $2`)).
+			WithArgs(1, "like").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectExec(touchPostSQL).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.reactionRepo.RemoveReaction(context.Background(), reaction)
+		suite.NoError(err)
+	})
+
+	suite.Run("success not added", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(deleteSQL).
+			WithArgs(1, 7, "like").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectCommit()
+
+		err := suite.reactionRepo.RemoveReaction(context.Background(), reaction)
+		suite.NoError(err)
+	})
+}
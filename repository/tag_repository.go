@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"expvar"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrphanTagsRemoved counts the tags removed because no post was using them
+// anymore, published on /debug/vars.
+var OrphanTagsRemoved = expvar.NewInt("orphan_tags_removed_total")
+
+type (
+	TagRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db}
+}
+
+func (tr *TagRepository) GetTags(ctx context.Context, limit, offset int) ([]model.TagSummary, int64, error) {
+	var total int64
+	err := tr.db.WithContext(ctx).Model(&model.Tag{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.TagSummary{}
+	err = tagSummaries(tr.db.WithContext(ctx)).
+		Order("tags.label").
+		Limit(limit).
+		Offset(offset).
+		Scan(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (tr *TagRepository) GetTag(ctx context.Context, id int) (*model.Tag, error) {
+	res := model.Tag{}
+	err := tr.db.WithContext(ctx).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetTagSummary gets the tag along with the number of published posts
+// labeled with it.
+func (tr *TagRepository) GetTagSummary(ctx context.Context, id int) (*model.TagSummary, error) {
+	res := model.TagSummary{}
+	err := tagSummaries(tr.db.WithContext(ctx)).
+		Where("tags.id = ?", id).
+		Take(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetTagByLabel finds the tag with label regardless of its case.
+func (tr *TagRepository) GetTagByLabel(ctx context.Context, label string) (*model.Tag, error) {
+	res := model.Tag{}
+	err := tr.db.WithContext(ctx).Where("lower(label) = lower(?)", label).First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetTagPosts lists the published posts labeled with the tag.
+func (tr *TagRepository) GetTagPosts(ctx context.Context, id, limit, offset int) ([]model.Post, int64, error) {
+	taggedPosts := tr.db.Table("post_tags").Select("post_tags.post_id").Where("post_tags.tag_id = ?", id)
+
+	var total int64
+	err := tr.db.WithContext(ctx).Model(&model.Post{}).
+		Where("posts.id IN (?) AND posts.status = ?", taggedPosts, model.PostStatusPublished).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := []model.Post{}
+	err = tr.db.WithContext(ctx).Model(&model.Post{}).
+		Where("posts.id IN (?) AND posts.status = ?", taggedPosts, model.PostStatusPublished).
+		Preload("Tags").Preload("User").Preload("ReactionCounts").
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+func (tr *TagRepository) GetAllTags(ctx context.Context) ([]model.Tag, error) {
+	res := []model.Tag{}
+	err := tr.db.WithContext(ctx).Order("id").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (tr *TagRepository) UpdateTag(ctx context.Context, req model.Tag) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := touchTaggedPosts(tx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Tag{ID: req.ID}).Updates(model.Tag{Label: req.Label, Slug: req.Slug}).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MergeTag moves every post of the source tag to the target tag and removes
+// the source tag.
+func (tr *TagRepository) MergeTag(ctx context.Context, sourceID, targetID int) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := touchTaggedPosts(tx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`INSERT INTO post_tags ("post_id","tag_id") SELECT post_id, $1 FROM post_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`, targetID, sourceID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`DELETE FROM post_tags WHERE tag_id=$1;`, sourceID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Delete(&model.Tag{ID: sourceID}).Error
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTag removes the tag and detaches it from every post. It fails with
+// model.ErrLastTag when a post has no other tag, which is checked once the
+// posts are locked by bumping their version, so that their tags cannot change
+// in the meantime.
+func (tr *TagRepository) DeleteTag(ctx context.Context, id int) error {
+	err := tr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := touchTaggedPosts(tx, id)
+		if err != nil {
+			return err
+		}
+
+		var lastTagged int64
+		err = tx.Table("post_tags").
+			Where("tag_id = ? AND NOT EXISTS (SELECT 1 FROM post_tags other WHERE other.post_id = post_tags.post_id AND other.tag_id <> post_tags.tag_id)", id).
+			Count(&lastTagged).Error
+		if err != nil {
+			return err
+		}
+
+		if lastTagged > 0 {
+			return model.ErrLastTag
+		}
+
+		err = tx.Exec(`DELETE FROM post_tags WHERE tag_id=$1;`, id).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Delete(&model.Tag{ID: id}).Error
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOrphanTags removes every tag that is not used by any post and returns
+// how many were removed.
+func (tr *TagRepository) DeleteOrphanTags(ctx context.Context) (int64, error) {
+	return deleteOrphanTags(tr.db.WithContext(ctx), nil)
+}
+
+// tagSummaries selects the tags along with the number of published posts
+// labeled with them.
+func tagSummaries(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Tag{}).
+		Select("tags.id, tags.label, COUNT(posts.id) AS post_count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = ?", model.PostStatusPublished).
+		Group("tags.id")
+}
+
+// touchTaggedPosts bumps the version of the posts labeled with the tag, since
+// changing the tag changes them as well.
+func touchTaggedPosts(tx *gorm.DB, tagID int) error {
+	return tx.Exec(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`, tagID).Error
+}
+
+// deleteOrphanTags removes the tags not used by any post, limited to ids
+// unless it is nil. The tags locked by a transaction attaching them to a post
+// are skipped, they are not orphan anymore once it commits.
+func deleteOrphanTags(db *gorm.DB, ids []int) (int64, error) {
+	orphans := db.Model(&model.Tag{}).
+		Select("id").
+		Where("NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id)").
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	if ids != nil {
+		orphans = orphans.Where("tags.id IN ?", ids)
+	}
+
+	res := db.Where("id IN (?)", orphans).Delete(&model.Tag{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	OrphanTagsRemoved.Add(res.RowsAffected)
+	return res.RowsAffected, nil
+}
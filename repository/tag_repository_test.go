@@ -0,0 +1,340 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TestTagRepositorySuite struct {
+	suite.Suite
+
+	sqlDB   *sql.DB
+	gormDB  *gorm.DB
+	mock    sqlmock.Sqlmock
+	tagRepo *TagRepository
+}
+
+func (suite *TestTagRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.tagRepo = NewTagRepository(gormDB)
+}
+
+func (suite *TestTagRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestTagRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestTagRepositorySuite))
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetTags() {
+	suite.Run("err count", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "tags"`)).
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.tagRepo.GetTags(context.Background(), 10, 0)
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "tags"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT tags.id, tags.label, COUNT(posts.id) AS post_count FROM "tags" LEFT JOIN post_tags ON post_tags.tag_id = tags.id LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = $1 GROUP BY "tags"."id" ORDER BY tags.label LIMIT $2 OFFSET $3`)).
+			WithArgs(model.PostStatusPublished, 10, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "post_count"}).AddRow(1, "go", 3).AddRow(2, "sql", 0))
+
+		res, total, err := suite.tagRepo.GetTags(context.Background(), 10, 5)
+		suite.NoError(err)
+		suite.Equal(int64(2), total)
+		suite.Equal([]model.TagSummary{{ID: 1, Label: "go", PostCount: 3}, {ID: 2, Label: "sql"}}, res)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetTag() {
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			WithArgs(1, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		res, err := suite.tagRepo.GetTag(context.Background(), 1)
+		suite.True(errors.Is(err, gorm.ErrRecordNotFound))
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE "tags"."id" = $1 ORDER BY "tags"."id" LIMIT $2`)).
+			WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "go"))
+
+		res, err := suite.tagRepo.GetTag(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(&model.Tag{ID: 1, Label: "go"}, res)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetTagSummary() {
+	summarySQL := regexp.QuoteMeta(`SELECT tags.id, tags.label, COUNT(posts.id) AS post_count FROM "tags" LEFT JOIN post_tags ON post_tags.tag_id = tags.id LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = $1 WHERE tags.id = $2 GROUP BY "tags"."id" LIMIT $3`)
+
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(summarySQL).
+			WithArgs(model.PostStatusPublished, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "post_count"}))
+
+		res, err := suite.tagRepo.GetTagSummary(context.Background(), 1)
+		suite.True(errors.Is(err, gorm.ErrRecordNotFound))
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(summarySQL).
+			WithArgs(model.PostStatusPublished, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "post_count"}).AddRow(1, "go", 3))
+
+		res, err := suite.tagRepo.GetTagSummary(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(&model.TagSummary{ID: 1, Label: "go", PostCount: 3}, res)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetTagByLabel() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE lower(label) = lower($1) ORDER BY "tags"."id" LIMIT $2`)).
+			WithArgs("go", 1).WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "go"))
+
+		res, err := suite.tagRepo.GetTagByLabel(context.Background(), "go")
+		suite.NoError(err)
+		suite.Equal(&model.Tag{ID: 1, Label: "go"}, res)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetAllTags() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" ORDER BY id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label", "slug"}).AddRow(1, "Go", "").AddRow(2, "go", "go"))
+
+		res, err := suite.tagRepo.GetAllTags(context.Background())
+		suite.NoError(err)
+		suite.Equal([]model.Tag{{ID: 1, Label: "Go"}, {ID: 2, Label: "go", Slug: "go"}}, res)
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" ORDER BY id`)).
+			WillReturnError(errors.New("err"))
+
+		res, err := suite.tagRepo.GetAllTags(context.Background())
+		suite.Error(err)
+		suite.Nil(res)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_GetTagPosts() {
+	suite.Run("err", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE (posts.id IN (SELECT post_tags.post_id FROM "post_tags" WHERE post_tags.tag_id = $1) AND posts.status = $2)`)).
+			WithArgs(1, model.PostStatusPublished).
+			WillReturnError(errors.New("err"))
+
+		res, total, err := suite.tagRepo.GetTagPosts(context.Background(), 1, 10, 0)
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Zero(total)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT count(*) FROM "posts" WHERE (posts.id IN (SELECT post_tags.post_id FROM "post_tags" WHERE post_tags.tag_id = $1) AND posts.status = $2)`)).
+			WithArgs(1, model.PostStatusPublished).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "posts" WHERE (posts.id IN (SELECT post_tags.post_id FROM "post_tags" WHERE post_tags.tag_id = $1) AND posts.status = $2) AND "posts"."deleted_at" IS NULL ORDER BY id desc LIMIT $3`)).
+			WithArgs(1, model.PostStatusPublished, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(1, "a", "b"))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_reaction_counts" WHERE "post_reaction_counts"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "kind", "count"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "post_tags" WHERE "post_tags"."post_id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}).AddRow(1, 1))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "label"}).AddRow(1, "go"))
+
+		res, total, err := suite.tagRepo.GetTagPosts(context.Background(), 1, 10, 0)
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(int64(1), total)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_UpdateTag() {
+	touchSQL := regexp.QuoteMeta(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tags" SET "label"=$1,"slug"=$2 WHERE "id" = $3`)).
+			WithArgs("golang", "golang", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.tagRepo.UpdateTag(context.Background(), model.Tag{ID: 1, Label: "golang", Slug: "golang"})
+		suite.NoError(err)
+	})
+
+	suite.Run("err touch posts", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := suite.tagRepo.UpdateTag(context.Background(), model.Tag{ID: 1, Label: "golang", Slug: "golang"})
+		suite.Error(err)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_MergeTag() {
+	touchSQL := regexp.QuoteMeta(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") SELECT post_id, $1 FROM post_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`)).
+			WithArgs(2, 1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE tag_id=$1;`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.tagRepo.MergeTag(context.Background(), 1, 2)
+		suite.NoError(err)
+	})
+
+	suite.Run("err repoint post tags", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") SELECT post_id, $1 FROM post_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`)).
+			WithArgs(2, 1).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := suite.tagRepo.MergeTag(context.Background(), 1, 2)
+		suite.Error(err)
+	})
+
+	suite.Run("err delete tag", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO post_tags ("post_id","tag_id") SELECT post_id, $1 FROM post_tags WHERE tag_id = $2 ON CONFLICT DO NOTHING`)).
+			WithArgs(2, 1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE tag_id=$1;`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(1).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := suite.tagRepo.MergeTag(context.Background(), 1, 2)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_DeleteTag() {
+	touchSQL := regexp.QuoteMeta(`UPDATE posts SET version = version + 1, updated_at = now() WHERE id IN (SELECT post_id FROM post_tags WHERE tag_id = $1);`)
+	lastTaggedSQL := regexp.QuoteMeta(`SELECT count(*) FROM "post_tags" WHERE tag_id = $1 AND NOT EXISTS (SELECT 1 FROM post_tags other WHERE other.post_id = post_tags.post_id AND other.tag_id <> post_tags.tag_id)`)
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectQuery(lastTaggedSQL).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE tag_id=$1;`)).
+			WithArgs(1).WillReturnResult(driver.ResultNoRows)
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE "tags"."id" = $1`)).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.tagRepo.DeleteTag(context.Background(), 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("err last tag of a post", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectQuery(lastTaggedSQL).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		suite.mock.ExpectRollback()
+
+		err := suite.tagRepo.DeleteTag(context.Background(), 1)
+		suite.ErrorIs(err, model.ErrLastTag)
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(touchSQL).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		suite.mock.ExpectQuery(lastTaggedSQL).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM post_tags WHERE tag_id=$1;`)).
+			WithArgs(1).WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		err := suite.tagRepo.DeleteTag(context.Background(), 1)
+		suite.Error(err)
+	})
+}
+
+func (suite *TestTagRepositorySuite) TestTagRepository_DeleteOrphanTags() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE id IN (SELECT "id" FROM "tags" WHERE NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id) FOR UPDATE SKIP LOCKED)`)).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		suite.mock.ExpectCommit()
+
+		removed := OrphanTagsRemoved.Value()
+		res, err := suite.tagRepo.DeleteOrphanTags(context.Background())
+		suite.NoError(err)
+		suite.Equal(int64(3), res)
+		suite.Equal(removed+3, OrphanTagsRemoved.Value())
+	})
+
+	suite.Run("err", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "tags" WHERE id IN (SELECT "id" FROM "tags" WHERE NOT EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id) FOR UPDATE SKIP LOCKED)`)).
+			WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		res, err := suite.tagRepo.DeleteOrphanTags(context.Background())
+		suite.Error(err)
+		suite.Zero(res)
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// CreateUser creates the user, returning it with its id.
+func (ur *UserRepository) CreateUser(ctx context.Context, req model.User) (*model.User, error) {
+	user := model.User{
+		Name:  req.Name,
+		Email: req.Email,
+	}
+
+	err := ur.db.WithContext(ctx).Create(&user).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (ur *UserRepository) GetUser(ctx context.Context, id int) (*model.User, error) {
+	res := model.User{}
+	err := ur.db.WithContext(ctx).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	res := model.User{}
+	err := ur.db.WithContext(ctx).Where("email = ?", email).First(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (ur *UserRepository) UpdateUserRole(ctx context.Context, id int, role string) error {
+	return ur.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("role", role).Error
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/repository"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type TestUserRepositorySuite struct {
+	suite.Suite
+
+	sqlDB    *sql.DB
+	gormDB   *gorm.DB
+	mock     sqlmock.Sqlmock
+	userRepo *UserRepository
+}
+
+func (suite *TestUserRepositorySuite) SetupSuite() {
+	sqlDB, gormDB, mock := setupDbMock(suite.T())
+
+	suite.sqlDB = sqlDB
+	suite.gormDB = gormDB
+	suite.mock = mock
+	suite.userRepo = NewUserRepository(gormDB)
+}
+
+func (suite *TestUserRepositorySuite) TearDownSuite() {
+	suite.sqlDB.Close()
+}
+
+func TestUserRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TestUserRepositorySuite))
+}
+
+func (suite *TestUserRepositorySuite) TestUserRepository_CreateUser() {
+	insertSQL := regexp.QuoteMeta(`INSERT INTO "users" ("name","email","role") VALUES ($1,$2,$3) RETURNING "created_at","id"`)
+
+	suite.Run("err", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(insertSQL).
+			WithArgs("jane", "jane@example.com", "author").
+			WillReturnError(errors.New("err"))
+		suite.mock.ExpectRollback()
+
+		res, err := suite.userRepo.CreateUser(context.Background(), model.User{Name: "jane", Email: "jane@example.com"})
+		suite.Error(err)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectQuery(insertSQL).
+			WithArgs("jane", "jane@example.com", "author").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		suite.mock.ExpectCommit()
+
+		res, err := suite.userRepo.CreateUser(context.Background(), model.User{Name: "jane", Email: "jane@example.com"})
+		suite.NoError(err)
+		suite.Equal(7, res.ID)
+	})
+}
+
+func (suite *TestUserRepositorySuite) TestUserRepository_GetUser() {
+	suite.Run("err not found", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1 ORDER BY "users"."id" LIMIT $2`)).
+			WithArgs(7, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		res, err := suite.userRepo.GetUser(context.Background(), 7)
+		suite.ErrorIs(err, gorm.ErrRecordNotFound)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE "users"."id" = $1 ORDER BY "users"."id" LIMIT $2`)).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(7, "jane", "jane@example.com"))
+
+		res, err := suite.userRepo.GetUser(context.Background(), 7)
+		suite.NoError(err)
+		suite.Equal("jane", res.Name)
+	})
+}
+
+func (suite *TestUserRepositorySuite) TestUserRepository_GetUserByEmail() {
+	suite.Run("success", func() {
+		suite.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "users" WHERE email = $1 ORDER BY "users"."id" LIMIT $2`)).
+			WithArgs("jane@example.com", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(7, "jane", "jane@example.com"))
+
+		res, err := suite.userRepo.GetUserByEmail(context.Background(), "jane@example.com")
+		suite.NoError(err)
+		suite.Equal(7, res.ID)
+	})
+}
+
+func (suite *TestUserRepositorySuite) TestUserRepository_UpdateUserRole() {
+	suite.Run("success", func() {
+		suite.mock.ExpectBegin()
+		suite.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "users" SET "role"=$1 WHERE id = $2`)).
+			WithArgs("editor", 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		suite.mock.ExpectCommit()
+
+		err := suite.userRepo.UpdateUserRole(context.Background(), 7, "editor")
+		suite.NoError(err)
+	})
+}
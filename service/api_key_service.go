@@ -0,0 +1,214 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAPIKeysLimit = 20
+	// apiKeyPrefix starts every key, so that leaked keys are easy to find.
+	apiKeyPrefix = "afk_"
+	// apiKeyShownLength is how much of the key is kept as its prefix.
+	apiKeyShownLength = 12
+	// apiKeyTouchInterval is how often the last use of a key is recorded.
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	errInvalidAPIKey    = dto.ErrorUnauthorized{Message: "invalid api key"}
+	errAPIKeyManagement = dto.ErrorForbidden{Message: "api keys cannot manage api keys"}
+	errNotAuthenticated = dto.ErrorUnauthorized{Message: "authentication required"}
+)
+
+type (
+	IAPIKeyRepository interface {
+		CreateAPIKey(ctx context.Context, req model.APIKey) (*model.APIKey, error)
+		GetAPIKeys(ctx context.Context, userID, limit, offset int) ([]model.APIKey, int64, error)
+		GetAPIKey(ctx context.Context, id int) (*model.APIKey, error)
+		GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+		RevokeAPIKey(ctx context.Context, id int, revokedAt time.Time) error
+		TouchAPIKey(ctx context.Context, id int, usedAt, usedBefore time.Time) error
+	}
+
+	APIKeyService struct {
+		apiKeyRepository IAPIKeyRepository
+		now              func() time.Time
+	}
+)
+
+func NewAPIKeyService(apiKeyRepository IAPIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepository: apiKeyRepository,
+		now:              time.Now,
+	}
+}
+
+// CreateAPIKey creates a key acting on behalf of the user of ctx, the key is
+// only returned here.
+func (as *APIKeyService) CreateAPIKey(ctx context.Context, req dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	userID, err := apiKeyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey, err := as.apiKeyRepository.CreateAPIKey(ctx, model.APIKey{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Prefix: key[:apiKeyShownLength],
+		Hash:   hashAPIKey(key),
+		Scopes: req.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CreateAPIKeyResponse{
+		GetAPIKeyResponse: newGetAPIKeyResponse(*apiKey),
+		Key:               key,
+	}, nil
+}
+
+// GetAPIKeys lists the keys of the user of ctx, the latest first.
+func (as *APIKeyService) GetAPIKeys(ctx context.Context, req dto.GetAPIKeysRequest) ([]dto.GetAPIKeyResponse, *dto.Pagination, error) {
+	userID, err := apiKeyManager(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAPIKeysLimit
+	}
+
+	apiKeys, total, err := as.apiKeyRepository.GetAPIKeys(ctx, userID, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetAPIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		res[i] = newGetAPIKeyResponse(apiKey)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// RevokeAPIKey revokes a key of the user of ctx, the keys of other users are
+// not found.
+func (as *APIKeyService) RevokeAPIKey(ctx context.Context, id int) error {
+	userID, err := apiKeyManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := as.apiKeyRepository.GetAPIKey(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if apiKey == nil || apiKey.UserID != userID {
+		return dto.ErrorNotFound{
+			EntityName: "api key",
+			EntityID:   id,
+		}
+	}
+
+	err = as.apiKeyRepository.RevokeAPIKey(ctx, id, as.now())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the principal the key acts as, with the role of
+// its user, as long as the key has not been revoked, and records its use.
+func (as *APIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	apiKey, err := as.apiKeyRepository.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return auth.Principal{}, errInvalidAPIKey
+		}
+		return auth.Principal{}, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return auth.Principal{}, errInvalidAPIKey
+	}
+
+	now := as.now()
+	err = as.apiKeyRepository.TouchAPIKey(ctx, apiKey.ID, now, now.Add(-apiKeyTouchInterval))
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	principal := auth.Principal{
+		UserID:   apiKey.UserID,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	}
+	if apiKey.User != nil {
+		principal.Role = auth.Role(apiKey.User.Role)
+	}
+
+	return principal, nil
+}
+
+// apiKeyManager returns the user of ctx managing their keys, which has to be
+// authenticated with a token.
+func apiKeyManager(ctx context.Context) (int, error) {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return 0, errNotAuthenticated
+	}
+
+	if principal.APIKeyID != 0 {
+		return 0, errAPIKeyManagement
+	}
+
+	return principal.UserID, nil
+}
+
+// hashAPIKey hashes the key to store and look it up. The keys are random
+// enough for a fast hash, unlike passwords.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newGetAPIKeyResponse(apiKey model.APIKey) dto.GetAPIKeyResponse {
+	return dto.GetAPIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
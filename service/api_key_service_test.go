@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestAPIKeyServiceSuite struct {
+	suite.Suite
+
+	MockAPIKeyRepo *gomockService.MockIAPIKeyRepository
+	As             *APIKeyService
+	Ctrl           *gomock.Controller
+}
+
+func (suite *TestAPIKeyServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockAPIKeyRepo = gomockService.NewMockIAPIKeyRepository(suite.Ctrl)
+	suite.As = NewAPIKeyService(suite.MockAPIKeyRepo)
+}
+
+func (suite *TestAPIKeyServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAPIKeyServiceSuite))
+}
+
+func (suite *TestAPIKeyServiceSuite) TestAPIKeyService_CreateAPIKey() {
+	req := dto.CreateAPIKeyRequest{Name: "ingestion", Scopes: []string{auth.ScopePostsWrite}}
+	userCtx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7})
+
+	suite.Run("error created with an api key", func() {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7, APIKeyID: 1})
+
+		res, err := suite.As.CreateAPIKey(ctx, req)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "api keys cannot manage api keys"})
+		suite.Nil(res)
+	})
+
+	suite.Run("error when create", func() {
+		suite.MockAPIKeyRepo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(nil, errors.New("err from db"))
+
+		res, err := suite.As.CreateAPIKey(userCtx, req)
+		suite.Error(err)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		var stored model.APIKey
+		suite.MockAPIKeyRepo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apiKey model.APIKey) (*model.APIKey, error) {
+			stored = apiKey
+			apiKey.ID = 1
+			return &apiKey, nil
+		})
+
+		res, err := suite.As.CreateAPIKey(userCtx, req)
+		suite.NoError(err)
+		suite.True(strings.HasPrefix(res.Key, "afk_"))
+		suite.Equal(res.Key[:12], res.Prefix)
+		suite.Equal(7, stored.UserID)
+		suite.Equal([]string{auth.ScopePostsWrite}, stored.Scopes)
+		suite.NotContains(stored.Hash, res.Key)
+
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), stored.Hash).Return(&stored, nil)
+		suite.MockAPIKeyRepo.EXPECT().TouchAPIKey(gomock.Any(), stored.ID, gomock.Any(), gomock.Any()).Return(nil)
+
+		principal, err := suite.As.AuthenticateAPIKey(context.Background(), res.Key)
+		suite.NoError(err)
+		suite.Equal(7, principal.UserID)
+	})
+}
+
+func (suite *TestAPIKeyServiceSuite) TestAPIKeyService_GetAPIKeys() {
+	suite.Run("success", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKeys(gomock.Any(), 7, 20, 0).Return([]model.APIKey{{ID: 1, Name: "ingestion"}}, int64(1), nil)
+
+		res, pagination, err := suite.As.GetAPIKeys(auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7}), dto.GetAPIKeysRequest{})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(int64(1), pagination.Total)
+	})
+}
+
+func (suite *TestAPIKeyServiceSuite) TestAPIKeyService_RevokeAPIKey() {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7})
+
+	suite.Run("error not found", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKey(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.As.RevokeAPIKey(ctx, 1)
+		suite.EqualError(err, "cannot find api key with id 1")
+	})
+
+	suite.Run("error key of another user", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKey(gomock.Any(), 1).Return(&model.APIKey{ID: 1, UserID: 8}, nil)
+
+		err := suite.As.RevokeAPIKey(ctx, 1)
+		suite.EqualError(err, "cannot find api key with id 1")
+	})
+
+	suite.Run("success", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKey(gomock.Any(), 1).Return(&model.APIKey{ID: 1, UserID: 7}, nil)
+		suite.MockAPIKeyRepo.EXPECT().RevokeAPIKey(gomock.Any(), 1, gomock.Any()).Return(nil)
+
+		err := suite.As.RevokeAPIKey(ctx, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestAPIKeyServiceSuite) TestAPIKeyService_AuthenticateAPIKey() {
+	suite.Run("error unknown key", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.As.AuthenticateAPIKey(context.Background(), "afk_unknown")
+		suite.ErrorIs(err, dto.ErrorUnauthorized{Message: "invalid api key"})
+	})
+
+	suite.Run("error revoked key", func() {
+		revokedAt := time.Now()
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(&model.APIKey{ID: 1, RevokedAt: &revokedAt}, nil)
+
+		_, err := suite.As.AuthenticateAPIKey(context.Background(), "afk_revoked")
+		suite.ErrorIs(err, dto.ErrorUnauthorized{Message: "invalid api key"})
+	})
+
+	suite.Run("success", func() {
+		suite.MockAPIKeyRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(&model.APIKey{
+			ID:     1,
+			UserID: 7,
+			User:   &model.User{ID: 7, Role: "editor"},
+			Scopes: []string{auth.ScopePostsRead},
+		}, nil)
+		suite.MockAPIKeyRepo.EXPECT().TouchAPIKey(gomock.Any(), 1, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ int, usedAt, usedBefore time.Time) error {
+			suite.Equal(time.Minute, usedAt.Sub(usedBefore))
+			return nil
+		})
+
+		principal, err := suite.As.AuthenticateAPIKey(context.Background(), "afk_key")
+		suite.NoError(err)
+		suite.Equal(auth.Principal{UserID: 7, Role: auth.RoleEditor, APIKeyID: 1, Scopes: []string{auth.ScopePostsRead}}, principal)
+	})
+}
@@ -0,0 +1,410 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAttachmentsLimit  = 20
+	defaultMaxAttachmentSize = 10 << 20
+	// sniffLen is the number of bytes http.DetectContentType looks at.
+	sniffLen               = 512
+	orphanAttachmentsBatch = 100
+)
+
+// allowedAttachmentTypes are the content types the attachments can be
+// sniffed as.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+	"video/mp4":       true,
+	"audio/mpeg":      true,
+}
+
+var errEmptyAttachment = dto.ErrorBadRequest{Message: "attachment cannot be empty"}
+
+type (
+	IAttachmentRepository interface {
+		CreateAttachment(ctx context.Context, req model.Attachment) (*model.Attachment, error)
+		GetAttachments(ctx context.Context, postID, limit, offset int) ([]model.Attachment, int64, error)
+		GetAttachment(ctx context.Context, postID, id int) (*model.Attachment, error)
+		DeleteAttachment(ctx context.Context, id int) error
+		GetOrphanAttachments(ctx context.Context, limit int) ([]model.Attachment, error)
+	}
+
+	// IAttachmentPostRepository gets the posts the files are attached to.
+	IAttachmentPostRepository interface {
+		GetPost(ctx context.Context, id int) (*model.Post, error)
+	}
+
+	// IBlobStore keeps the content of the attachments, see the storage
+	// package for the local disk and the S3 implementations.
+	IBlobStore interface {
+		Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+		Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	AttachmentService struct {
+		attachmentRepository IAttachmentRepository
+		postRepository       IAttachmentPostRepository
+		blobStore            IBlobStore
+		maxSize              int64
+	}
+
+	AttachmentServiceOption func(*AttachmentService)
+)
+
+// WithMaxAttachmentSize sets the size in bytes above which the attachments
+// are rejected, 10 MiB by default.
+func WithMaxAttachmentSize(size int64) AttachmentServiceOption {
+	return func(as *AttachmentService) {
+		as.maxSize = size
+	}
+}
+
+func NewAttachmentService(attachmentRepository IAttachmentRepository, postRepository IAttachmentPostRepository, blobStore IBlobStore, opts ...AttachmentServiceOption) *AttachmentService {
+	as := &AttachmentService{
+		attachmentRepository: attachmentRepository,
+		postRepository:       postRepository,
+		blobStore:            blobStore,
+		maxSize:              defaultMaxAttachmentSize,
+	}
+
+	for _, opt := range opts {
+		opt(as)
+	}
+
+	return as
+}
+
+// MaxSize is the size in bytes above which the attachments are rejected.
+func (as *AttachmentService) MaxSize() int64 {
+	return as.maxSize
+}
+
+// UploadAttachment attaches the size bytes of content to the post, which the
+// user of ctx must be allowed to edit. The content type is sniffed from the
+// content and must be one of allowedAttachmentTypes.
+func (as *AttachmentService) UploadAttachment(ctx context.Context, postID int, filename string, content io.Reader, size int64) (*dto.GetAttachmentResponse, error) {
+	post, err := as.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizePost(ctx, post, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > as.maxSize {
+		return nil, errAttachmentTooLarge(as.maxSize)
+	}
+
+	if size <= 0 {
+		return nil, errEmptyAttachment
+	}
+
+	head := make([]byte, min(size, sniffLen))
+	_, err = io.ReadFull(content, head)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedAttachmentTypes[contentType] {
+		return nil, dto.ErrorUnsupportedMediaType{Message: fmt.Sprintf("attachments of type %s are not allowed", contentType)}
+	}
+
+	key, err := attachmentKey(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.New()
+	content = io.TeeReader(io.MultiReader(bytes.NewReader(head), io.LimitReader(content, size-int64(len(head)))), checksum)
+	err = as.blobStore.Put(ctx, key, content, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := auth.UserID(ctx)
+	attachment, err := as.attachmentRepository.CreateAttachment(ctx, model.Attachment{
+		PostID:      postID,
+		UserID:      &userID,
+		Filename:    attachmentFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		Key:         key,
+		Checksum:    hex.EncodeToString(checksum.Sum(nil)),
+	})
+	if err != nil {
+		// the blob would never be referenced
+		_ = as.blobStore.Delete(context.WithoutCancel(ctx), key)
+		return nil, err
+	}
+
+	res := newGetAttachmentResponse(*attachment)
+	return &res, nil
+}
+
+// GetAttachments lists the attachments of the post, the oldest first.
+func (as *AttachmentService) GetAttachments(ctx context.Context, postID int, req dto.GetAttachmentsRequest) ([]dto.GetAttachmentResponse, *dto.Pagination, error) {
+	_, err := as.getPost(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAttachmentsLimit
+	}
+
+	attachments, total, err := as.attachmentRepository.GetAttachments(ctx, postID, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetAttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		res[i] = newGetAttachmentResponse(attachment)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// OpenAttachment gets the attachment of the post along with its content. The
+// content is only read from the blob store once it is read, from the offset
+// it was seeked to, so that ranges of large attachments are cheap to serve.
+func (as *AttachmentService) OpenAttachment(ctx context.Context, postID, id int) (*dto.GetAttachmentResponse, io.ReadSeekCloser, error) {
+	_, err := as.getPost(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := as.getAttachment(ctx, postID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := newGetAttachmentResponse(*attachment)
+	return &res, &blobReader{ctx: ctx, store: as.blobStore, key: attachment.Key, size: attachment.Size}, nil
+}
+
+// DeleteAttachment deletes the attachment of the post and its content, the
+// user of ctx must be allowed to edit the post.
+func (as *AttachmentService) DeleteAttachment(ctx context.Context, postID, id int) error {
+	post, err := as.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	err = authorizePost(ctx, post, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := as.getAttachment(ctx, postID, id)
+	if err != nil {
+		return err
+	}
+
+	// the blob goes first, a failure leaves the attachment to be deleted
+	// again instead of an unreferenced blob
+	err = as.blobStore.Delete(ctx, attachment.Key)
+	if err != nil {
+		return err
+	}
+
+	err = as.attachmentRepository.DeleteAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SweepOrphanAttachments deletes the attachments of the purged posts along
+// with their content. It is meant to be run periodically.
+func (as *AttachmentService) SweepOrphanAttachments(ctx context.Context) error {
+	for {
+		attachments, err := as.attachmentRepository.GetOrphanAttachments(ctx, orphanAttachmentsBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range attachments {
+			err = as.blobStore.Delete(ctx, attachment.Key)
+			if err != nil {
+				return err
+			}
+
+			err = as.attachmentRepository.DeleteAttachment(ctx, attachment.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(attachments) < orphanAttachmentsBatch {
+			return nil
+		}
+	}
+}
+
+// getPost gets the post, failing with a not found error when it does not
+// exist or the user of ctx cannot see it.
+func (as *AttachmentService) getPost(ctx context.Context, id int) (*model.Post, error) {
+	post, err := as.postRepository.GetPost(ctx, id)
+	if err == nil && !canSeePost(ctx, post) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "post",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func (as *AttachmentService) getAttachment(ctx context.Context, postID, id int) (*model.Attachment, error) {
+	attachment, err := as.attachmentRepository.GetAttachment(ctx, postID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "attachment",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func errAttachmentTooLarge(maxSize int64) dto.ErrorPayloadTooLarge {
+	return dto.ErrorPayloadTooLarge{Message: fmt.Sprintf("attachments cannot be larger than %d bytes", maxSize)}
+}
+
+// attachmentKey is a random key under the prefix of the post, the filenames
+// chosen by the users never reach the blob store.
+func attachmentKey(postID int) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("posts/%d/%s", postID, hex.EncodeToString(b)), nil
+}
+
+// attachmentFilename keeps the base name of the uploaded file, without the
+// directories some clients send along.
+func attachmentFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, `\`, "/"))
+	if filename == "." || filename == "/" {
+		return "attachment"
+	}
+
+	return filename
+}
+
+func newGetAttachmentResponse(attachment model.Attachment) dto.GetAttachmentResponse {
+	return dto.GetAttachmentResponse{
+		ID:          attachment.ID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Checksum:    attachment.Checksum,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// blobReader reads a blob as an io.ReadSeekCloser, opening it from the
+// current offset on the first read after each seek.
+type blobReader struct {
+	ctx    context.Context
+	store  IBlobStore
+	key    string
+	size   int64
+	offset int64
+	r      io.ReadCloser
+}
+
+func (br *blobReader) Read(p []byte) (int, error) {
+	if br.offset >= br.size {
+		return 0, io.EOF
+	}
+
+	if br.r == nil {
+		r, err := br.store.Get(br.ctx, br.key, br.offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		br.r = r
+	}
+
+	n, err := br.r.Read(p)
+	br.offset += int64(n)
+	return n, err
+}
+
+func (br *blobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += br.offset
+	case io.SeekEnd:
+		offset += br.size
+	}
+
+	if offset < 0 {
+		return 0, errors.New("seek before the start of the blob")
+	}
+
+	if offset != br.offset {
+		br.Close()
+		br.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (br *blobReader) Close() error {
+	if br.r == nil {
+		return nil
+	}
+
+	err := br.r.Close()
+	br.r = nil
+	return err
+}
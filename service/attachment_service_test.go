@@ -0,0 +1,266 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestAttachmentServiceSuite struct {
+	suite.Suite
+
+	MockAttachmentRepo *gomockService.MockIAttachmentRepository
+	MockPostRepo       *gomockService.MockIAttachmentPostRepository
+	MockBlobStore      *gomockService.MockIBlobStore
+	As                 *AttachmentService
+	Ctrl               *gomock.Controller
+}
+
+func (suite *TestAttachmentServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockAttachmentRepo = gomockService.NewMockIAttachmentRepository(suite.Ctrl)
+	suite.MockPostRepo = gomockService.NewMockIAttachmentPostRepository(suite.Ctrl)
+	suite.MockBlobStore = gomockService.NewMockIBlobStore(suite.Ctrl)
+	suite.As = NewAttachmentService(suite.MockAttachmentRepo, suite.MockPostRepo, suite.MockBlobStore, WithMaxAttachmentSize(1024))
+}
+
+func (suite *TestAttachmentServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestAttachmentServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestAttachmentServiceSuite))
+}
+
+func (suite *TestAttachmentServiceSuite) TestAttachmentService_UploadAttachment() {
+	userID := 7
+	author := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleAuthor})
+	post := &model.Post{ID: 1, UserID: &userID}
+	content := "hello world"
+	sum := sha256.Sum256([]byte(content))
+	checksum := hex.EncodeToString(sum[:])
+
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.As.UploadAttachment(author, 1, "a.txt", strings.NewReader(content), 11)
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error post of another user", func() {
+		otherID := 8
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished, UserID: &otherID}, nil)
+
+		_, err := suite.As.UploadAttachment(author, 1, "a.txt", strings.NewReader(content), 11)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:edit_any permission is required"})
+	})
+
+	suite.Run("error too large", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		_, err := suite.As.UploadAttachment(author, 1, "a.txt", strings.NewReader(content), 1025)
+		suite.ErrorIs(err, dto.ErrorPayloadTooLarge{Message: "attachments cannot be larger than 1024 bytes"})
+	})
+
+	suite.Run("error empty", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		_, err := suite.As.UploadAttachment(author, 1, "a.txt", strings.NewReader(""), 0)
+		suite.ErrorIs(err, dto.ErrorBadRequest{Message: "attachment cannot be empty"})
+	})
+
+	suite.Run("error unsupported type", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		_, err := suite.As.UploadAttachment(author, 1, "page.txt", strings.NewReader("<html><body>hi</body></html>"), 28)
+		suite.ErrorIs(err, dto.ErrorUnsupportedMediaType{Message: "attachments of type text/html are not allowed"})
+	})
+
+	suite.Run("error create deletes the blob", func() {
+		var key string
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(11), "text/plain").
+			DoAndReturn(func(ctx context.Context, k string, r io.Reader, size int64, contentType string) error {
+				key = k
+				_, err := io.ReadAll(r)
+				return err
+			})
+		suite.MockAttachmentRepo.EXPECT().CreateAttachment(gomock.Any(), gomock.Any()).Return(nil, errors.New("err db"))
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, k string) error {
+				suite.Equal(key, k)
+				return nil
+			})
+
+		_, err := suite.As.UploadAttachment(author, 1, "a.txt", strings.NewReader(content), 11)
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockBlobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(11), "text/plain").
+			DoAndReturn(func(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+				suite.Regexp(`^posts/1/[0-9a-f]{32}$`, key)
+				body, err := io.ReadAll(r)
+				suite.Equal(content, string(body))
+				return err
+			})
+		suite.MockAttachmentRepo.EXPECT().CreateAttachment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req model.Attachment) (*model.Attachment, error) {
+				suite.Equal(1, req.PostID)
+				suite.Equal(&userID, req.UserID)
+				suite.Equal("a.txt", req.Filename)
+				suite.Equal(checksum, req.Checksum)
+				req.ID = 3
+				return &req, nil
+			})
+
+		res, err := suite.As.UploadAttachment(author, 1, `C:\Users\jane\a.txt`, strings.NewReader(content), 11)
+		suite.NoError(err)
+		suite.Equal(&dto.GetAttachmentResponse{
+			ID:          3,
+			Filename:    "a.txt",
+			ContentType: "text/plain",
+			Size:        11,
+			Checksum:    checksum,
+		}, res)
+	})
+}
+
+func (suite *TestAttachmentServiceSuite) TestAttachmentService_GetAttachments() {
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := suite.As.GetAttachments(context.Background(), 1, dto.GetAttachmentsRequest{})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error draft anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft}, nil)
+
+		_, _, err := suite.As.GetAttachments(context.Background(), 1, dto.GetAttachmentsRequest{})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished}, nil)
+		suite.MockAttachmentRepo.EXPECT().GetAttachments(gomock.Any(), 1, 20, 0).
+			Return([]model.Attachment{{ID: 3, Filename: "a.txt", ContentType: "text/plain", Size: 11}}, int64(1), nil)
+
+		res, pagination, err := suite.As.GetAttachments(context.Background(), 1, dto.GetAttachmentsRequest{})
+		suite.NoError(err)
+		suite.Equal([]dto.GetAttachmentResponse{{ID: 3, Filename: "a.txt", ContentType: "text/plain", Size: 11}}, res)
+		suite.Equal(&dto.Pagination{Page: 1, PerPage: 20, Total: 1}, pagination)
+	})
+}
+
+func (suite *TestAttachmentServiceSuite) TestAttachmentService_OpenAttachment() {
+	published := &model.Post{ID: 1, Status: model.PostStatusPublished}
+
+	suite.Run("error draft anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft}, nil)
+
+		_, _, err := suite.As.OpenAttachment(context.Background(), 1, 3)
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(published, nil)
+		suite.MockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), 1, 3).Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := suite.As.OpenAttachment(context.Background(), 1, 3)
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "attachment", EntityID: 3})
+	})
+
+	suite.Run("success reads from the offset", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(published, nil)
+		suite.MockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), 1, 3).
+			Return(&model.Attachment{ID: 3, Key: "posts/1/abc", Size: 11}, nil)
+		suite.MockBlobStore.EXPECT().Get(gomock.Any(), "posts/1/abc", int64(6), int64(-1)).
+			Return(io.NopCloser(strings.NewReader("world")), nil)
+
+		res, content, err := suite.As.OpenAttachment(context.Background(), 1, 3)
+		suite.NoError(err)
+		suite.Equal(3, res.ID)
+		defer content.Close()
+
+		size, err := content.Seek(0, io.SeekEnd)
+		suite.NoError(err)
+		suite.Equal(int64(11), size)
+
+		_, err = content.Seek(6, io.SeekStart)
+		suite.NoError(err)
+
+		body, err := io.ReadAll(content)
+		suite.NoError(err)
+		suite.Equal("world", string(body))
+	})
+}
+
+func (suite *TestAttachmentServiceSuite) TestAttachmentService_DeleteAttachment() {
+	userID := 7
+	author := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleAuthor})
+	post := &model.Post{ID: 1, Status: model.PostStatusPublished, UserID: &userID}
+
+	suite.Run("error anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		err := suite.As.DeleteAttachment(context.Background(), 1, 3)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:edit_any permission is required"})
+	})
+
+	suite.Run("error delete blob", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), 1, 3).Return(&model.Attachment{ID: 3, Key: "posts/1/abc"}, nil)
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), "posts/1/abc").Return(errors.New("err store"))
+
+		err := suite.As.DeleteAttachment(author, 1, 3)
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockAttachmentRepo.EXPECT().GetAttachment(gomock.Any(), 1, 3).Return(&model.Attachment{ID: 3, Key: "posts/1/abc"}, nil)
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), "posts/1/abc").Return(nil)
+		suite.MockAttachmentRepo.EXPECT().DeleteAttachment(gomock.Any(), 3).Return(nil)
+
+		err := suite.As.DeleteAttachment(author, 1, 3)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestAttachmentServiceSuite) TestAttachmentService_SweepOrphanAttachments() {
+	suite.Run("success", func() {
+		suite.MockAttachmentRepo.EXPECT().GetOrphanAttachments(gomock.Any(), 100).
+			Return([]model.Attachment{{ID: 3, Key: "posts/1/abc"}, {ID: 4, Key: "posts/1/def"}}, nil)
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), "posts/1/abc").Return(nil)
+		suite.MockAttachmentRepo.EXPECT().DeleteAttachment(gomock.Any(), 3).Return(nil)
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), "posts/1/def").Return(nil)
+		suite.MockAttachmentRepo.EXPECT().DeleteAttachment(gomock.Any(), 4).Return(nil)
+
+		err := suite.As.SweepOrphanAttachments(context.Background())
+		suite.NoError(err)
+	})
+
+	suite.Run("error delete blob keeps the attachment", func() {
+		suite.MockAttachmentRepo.EXPECT().GetOrphanAttachments(gomock.Any(), 100).
+			Return([]model.Attachment{{ID: 3, Key: "posts/1/abc"}}, nil)
+		suite.MockBlobStore.EXPECT().Delete(gomock.Any(), "posts/1/abc").Return(errors.New("err store"))
+
+		err := suite.As.SweepOrphanAttachments(context.Background())
+		suite.Error(err)
+	})
+}
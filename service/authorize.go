@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+)
+
+// authorize fails when the role of the user of ctx lacks the permission.
+func authorize(ctx context.Context, permission auth.Permission) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+	if !principal.Can(permission) {
+		return errPermissionRequired(permission)
+	}
+
+	return nil
+}
+
+// authorizePost fails when the role of the user of ctx lacks the own
+// permission for their posts and the posts without author, or the any
+// permission for the posts of other users.
+func authorizePost(ctx context.Context, post *model.Post, own, any auth.Permission) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+	return authorize(ctx, principal.PostPermission(post.UserID, own, any))
+}
+
+// authorizeComment is authorizePost for the comments, the comments of deleted
+// users need the any permission.
+func authorizeComment(ctx context.Context, comment *model.Comment, own, any auth.Permission) error {
+	if comment.UserID == nil {
+		return authorize(ctx, any)
+	}
+
+	principal, _ := auth.PrincipalFrom(ctx)
+	return authorize(ctx, principal.PostPermission(comment.UserID, own, any))
+}
+
+// canSeePost tells whether the user of ctx can see the post. The posts that
+// are not published are only seen by their author and by the roles allowed to
+// edit any post, they do not exist for anyone else.
+func canSeePost(ctx context.Context, post *model.Post) bool {
+	if post.Status == model.PostStatusPublished {
+		return true
+	}
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return false
+	}
+
+	return post.UserID != nil && *post.UserID == principal.UserID || principal.Can(auth.PermEditAnyPost)
+}
+
+func errPermissionRequired(permission auth.Permission) dto.ErrorForbidden {
+	return dto.ErrorForbidden{Message: "the " + string(permission) + " permission is required"}
+}
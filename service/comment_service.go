@@ -0,0 +1,236 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+const defaultCommentsLimit = 20
+
+var (
+	errBlankComment     = dto.ErrorBadRequest{Message: "comment cannot be blank"}
+	errPostNotPublished = dto.ErrorConflict{Message: "only published posts can be commented"}
+)
+
+type (
+	ICommentRepository interface {
+		CreateComment(ctx context.Context, req model.Comment) (*model.Comment, error)
+		GetComments(ctx context.Context, postID, limit, offset int) ([]model.Comment, int64, error)
+		GetCommentReplies(ctx context.Context, ids []int) ([]model.Comment, error)
+		GetComment(ctx context.Context, postID, id int) (*model.Comment, error)
+		DeleteComment(ctx context.Context, id int) error
+	}
+
+	// ICommentPostRepository gets the posts the comments are left on.
+	ICommentPostRepository interface {
+		GetPost(ctx context.Context, id int) (*model.Post, error)
+	}
+
+	CommentService struct {
+		commentRepository ICommentRepository
+		postRepository    ICommentPostRepository
+	}
+)
+
+func NewCommentService(commentRepository ICommentRepository, postRepository ICommentPostRepository) *CommentService {
+	return &CommentService{
+		commentRepository: commentRepository,
+		postRepository:    postRepository,
+	}
+}
+
+// CreateComment leaves a comment of the user of ctx on the post, or replies
+// to a comment of the post. Only published posts can be commented.
+func (cs *CommentService) CreateComment(ctx context.Context, postID int, req dto.CreateCommentRequest) (*dto.GetCommentResponse, error) {
+	err := authorize(ctx, auth.PermCreateComment)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, errBlankComment
+	}
+
+	post, err := cs.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Status != model.PostStatusPublished {
+		return nil, errPostNotPublished
+	}
+
+	if req.ParentID != nil {
+		_, err = cs.commentRepository.GetComment(ctx, postID, *req.ParentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorBadRequest{Message: fmt.Sprintf("cannot find comment with id %d on the post", *req.ParentID)}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	userID := auth.UserID(ctx)
+	comment, err := cs.commentRepository.CreateComment(ctx, model.Comment{
+		PostID:   postID,
+		UserID:   &userID,
+		ParentID: req.ParentID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err = cs.commentRepository.GetComment(ctx, postID, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := newGetCommentResponse(*comment, nil)
+	return &res, nil
+}
+
+// GetComments lists the threads of the post, the oldest first. Each thread is
+// a comment along with all of its replies, only the threads are paginated.
+func (cs *CommentService) GetComments(ctx context.Context, postID int, req dto.GetCommentsRequest) ([]dto.GetCommentResponse, *dto.Pagination, error) {
+	_, err := cs.getPost(ctx, postID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultCommentsLimit
+	}
+
+	comments, total, err := cs.commentRepository.GetComments(ctx, postID, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	replies, err := cs.commentRepository.GetCommentReplies(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repliesByParent := map[int][]model.Comment{}
+	for _, reply := range replies {
+		repliesByParent[*reply.ParentID] = append(repliesByParent[*reply.ParentID], reply)
+	}
+
+	res := make([]dto.GetCommentResponse, len(comments))
+	for i, comment := range comments {
+		res[i] = newGetCommentResponse(comment, repliesByParent)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// DeleteComment deletes the comment of the post along with its replies.
+func (cs *CommentService) DeleteComment(ctx context.Context, postID, id int) error {
+	comment, err := cs.commentRepository.GetComment(ctx, postID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ErrorNotFound{
+				EntityName: "comment",
+				EntityID:   id,
+			}
+		}
+		return err
+	}
+
+	err = authorizeComment(ctx, comment, auth.PermDeleteComment, auth.PermDeleteAnyComment)
+	if err != nil {
+		return err
+	}
+
+	err = cs.commentRepository.DeleteComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPost gets the post, failing with a not found error when it does not
+// exist or the user of ctx cannot see it.
+func (cs *CommentService) getPost(ctx context.Context, id int) (*model.Post, error) {
+	post, err := cs.postRepository.GetPost(ctx, id)
+	if err == nil && !canSeePost(ctx, post) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "post",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// newGetCommentResponses makes the threads of the comments, the oldest first,
+// each with its replies among comments.
+func newGetCommentResponses(comments []model.Comment) []dto.GetCommentResponse {
+	repliesByParent := map[int][]model.Comment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			repliesByParent[*comment.ParentID] = append(repliesByParent[*comment.ParentID], comment)
+		}
+	}
+
+	res := []dto.GetCommentResponse{}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			res = append(res, newGetCommentResponse(comment, repliesByParent))
+		}
+	}
+
+	return res
+}
+
+// newGetCommentResponse makes the response of the comment, nesting its
+// replies found in repliesByParent.
+func newGetCommentResponse(comment model.Comment, repliesByParent map[int][]model.Comment) dto.GetCommentResponse {
+	res := dto.GetCommentResponse{
+		ID:        comment.ID,
+		ParentID:  comment.ParentID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		Replies:   []dto.GetCommentResponse{},
+	}
+	if comment.User != nil {
+		res.Author = &dto.Author{
+			ID:   comment.User.ID,
+			Name: comment.User.Name,
+		}
+	}
+
+	for _, reply := range repliesByParent[comment.ID] {
+		res.Replies = append(res.Replies, newGetCommentResponse(reply, repliesByParent))
+	}
+
+	return res
+}
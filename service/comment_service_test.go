@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestCommentServiceSuite struct {
+	suite.Suite
+
+	MockCommentRepo *gomockService.MockICommentRepository
+	MockPostRepo    *gomockService.MockICommentPostRepository
+	Cs              *CommentService
+	Ctrl            *gomock.Controller
+}
+
+func (suite *TestCommentServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockCommentRepo = gomockService.NewMockICommentRepository(suite.Ctrl)
+	suite.MockPostRepo = gomockService.NewMockICommentPostRepository(suite.Ctrl)
+	suite.Cs = NewCommentService(suite.MockCommentRepo, suite.MockPostRepo)
+}
+
+func (suite *TestCommentServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestCommentServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestCommentServiceSuite))
+}
+
+func (suite *TestCommentServiceSuite) TestCommentService_CreateComment() {
+	viewer := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7, Role: auth.RoleViewer})
+	published := &model.Post{ID: 1, Status: model.PostStatusPublished}
+	parentID := 2
+
+	suite.Run("error anonymously", func() {
+		_, err := suite.Cs.CreateComment(context.Background(), 1, dto.CreateCommentRequest{Body: "nice"})
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the comments:create permission is required"})
+	})
+
+	suite.Run("error blank body", func() {
+		_, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: "  "})
+		suite.ErrorIs(err, dto.ErrorBadRequest{Message: "comment cannot be blank"})
+	})
+
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: "nice"})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error draft of another user", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft}, nil)
+
+		_, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: "nice"})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error draft", func() {
+		userID := 7
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft, UserID: &userID}, nil)
+
+		_, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: "nice"})
+		suite.ErrorIs(err, dto.ErrorConflict{Message: "only published posts can be commented"})
+	})
+
+	suite.Run("error parent of another post", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(published, nil)
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, parentID).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: "nice", ParentID: &parentID})
+		suite.ErrorIs(err, dto.ErrorBadRequest{Message: "cannot find comment with id 2 on the post"})
+	})
+
+	suite.Run("success reply", func() {
+		userID := 7
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(published, nil)
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, parentID).Return(&model.Comment{ID: parentID, PostID: 1}, nil)
+		suite.MockCommentRepo.EXPECT().CreateComment(gomock.Any(), model.Comment{
+			PostID:   1,
+			UserID:   &userID,
+			ParentID: &parentID,
+			Body:     "nice",
+		}).Return(&model.Comment{ID: 3}, nil)
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(&model.Comment{
+			ID:       3,
+			PostID:   1,
+			UserID:   &userID,
+			User:     &model.User{ID: userID, Name: "jane"},
+			ParentID: &parentID,
+			Body:     "nice",
+		}, nil)
+
+		res, err := suite.Cs.CreateComment(viewer, 1, dto.CreateCommentRequest{Body: " nice ", ParentID: &parentID})
+		suite.NoError(err)
+		suite.Equal(&dto.GetCommentResponse{
+			ID:       3,
+			ParentID: &parentID,
+			Author:   &dto.Author{ID: userID, Name: "jane"},
+			Body:     "nice",
+			Replies:  []dto.GetCommentResponse{},
+		}, res)
+	})
+}
+
+func (suite *TestCommentServiceSuite) TestCommentService_GetComments() {
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := suite.Cs.GetComments(context.Background(), 1, dto.GetCommentsRequest{})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error draft anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft}, nil)
+
+		_, _, err := suite.Cs.GetComments(context.Background(), 1, dto.GetCommentsRequest{})
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error get replies", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished}, nil)
+		suite.MockCommentRepo.EXPECT().GetComments(gomock.Any(), 1, 20, 0).Return([]model.Comment{{ID: 1}}, int64(1), nil)
+		suite.MockCommentRepo.EXPECT().GetCommentReplies(gomock.Any(), []int{1}).Return(nil, errors.New("err from db"))
+
+		_, _, err := suite.Cs.GetComments(context.Background(), 1, dto.GetCommentsRequest{})
+		suite.Error(err)
+	})
+
+	suite.Run("success threaded", func() {
+		one, three := 1, 3
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished}, nil)
+		suite.MockCommentRepo.EXPECT().GetComments(gomock.Any(), 1, 2, 2).Return([]model.Comment{
+			{ID: 1, Body: "first"},
+			{ID: 2, Body: "second"},
+		}, int64(5), nil)
+		suite.MockCommentRepo.EXPECT().GetCommentReplies(gomock.Any(), []int{1, 2}).Return([]model.Comment{
+			{ID: 3, ParentID: &one, Body: "reply"},
+			{ID: 4, ParentID: &three, Body: "reply to reply"},
+			{ID: 5, ParentID: &one, Body: "another reply"},
+		}, nil)
+
+		res, pagination, err := suite.Cs.GetComments(context.Background(), 1, dto.GetCommentsRequest{Limit: 2, Offset: 2})
+		suite.NoError(err)
+		suite.Equal(&dto.Pagination{Page: 2, PerPage: 2, Total: 5}, pagination)
+		suite.Equal([]dto.GetCommentResponse{
+			{ID: 1, Body: "first", Replies: []dto.GetCommentResponse{
+				{ID: 3, ParentID: &one, Body: "reply", Replies: []dto.GetCommentResponse{
+					{ID: 4, ParentID: &three, Body: "reply to reply", Replies: []dto.GetCommentResponse{}},
+				}},
+				{ID: 5, ParentID: &one, Body: "another reply", Replies: []dto.GetCommentResponse{}},
+			}},
+			{ID: 2, Body: "second", Replies: []dto.GetCommentResponse{}},
+		}, res)
+	})
+}
+
+func (suite *TestCommentServiceSuite) TestCommentService_DeleteComment() {
+	authorID := 7
+	comment := &model.Comment{ID: 3, PostID: 1, UserID: &authorID}
+
+	suite.Run("error not found", func() {
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Cs.DeleteComment(context.Background(), 1, 3)
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "comment", EntityID: 3})
+	})
+
+	suite.Run("error by another author", func() {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 8, Role: auth.RoleAuthor})
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(comment, nil)
+
+		err := suite.Cs.DeleteComment(ctx, 1, 3)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the comments:delete_any permission is required"})
+	})
+
+	suite.Run("error comment of a deleted user", func() {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 8, Role: auth.RoleAuthor})
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(&model.Comment{ID: 3, PostID: 1}, nil)
+
+		err := suite.Cs.DeleteComment(ctx, 1, 3)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the comments:delete_any permission is required"})
+	})
+
+	suite.Run("success by the author", func() {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: authorID, Role: auth.RoleViewer})
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(comment, nil)
+		suite.MockCommentRepo.EXPECT().DeleteComment(gomock.Any(), 3).Return(nil)
+
+		err := suite.Cs.DeleteComment(ctx, 1, 3)
+		suite.NoError(err)
+	})
+
+	suite.Run("success by an editor", func() {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 8, Role: auth.RoleEditor})
+		suite.MockCommentRepo.EXPECT().GetComment(gomock.Any(), 1, 3).Return(comment, nil)
+		suite.MockCommentRepo.EXPECT().DeleteComment(gomock.Any(), 3).Return(nil)
+
+		err := suite.Cs.DeleteComment(ctx, 1, 3)
+		suite.NoError(err)
+	})
+}
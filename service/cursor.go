@@ -0,0 +1,67 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+)
+
+var errInvalidCursor = dto.ErrorBadRequest{Message: "invalid cursor"}
+
+// postCursor is the position of the last post served in a page. It is sent
+// to clients as an opaque "<payload>.<signature>" string so it can't be
+// forged to seek to arbitrary positions.
+type postCursor struct {
+	ID int `json:"id"`
+}
+
+func (ps *PostService) encodeCursor(cursor postCursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(ps.signCursor(payload)), nil
+}
+
+func (ps *PostService) decodeCursor(raw string) (postCursor, error) {
+	cursor := postCursor{}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return cursor, errInvalidCursor
+	}
+
+	encoding := base64.RawURLEncoding
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	if !hmac.Equal(signature, ps.signCursor(payload)) {
+		return cursor, errInvalidCursor
+	}
+
+	err = json.Unmarshal(payload, &cursor)
+	if err != nil || cursor.ID <= 0 {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+func (ps *PostService) signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, ps.cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
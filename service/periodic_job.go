@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PeriodicJob runs a task every interval in the background until it is
+// stopped. A failing run is logged and retried on the next tick.
+type PeriodicJob struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+	logger   *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPeriodicJob(name string, interval time.Duration, logger *zap.Logger, task func(ctx context.Context) error) *PeriodicJob {
+	return &PeriodicJob{
+		name:     name,
+		interval: interval,
+		task:     task,
+		logger:   logger,
+	}
+}
+
+func (pj *PeriodicJob) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	pj.cancel = cancel
+
+	pj.wg.Add(1)
+	go func() {
+		defer pj.wg.Done()
+
+		ticker := time.NewTicker(pj.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := pj.task(ctx); err != nil && ctx.Err() == nil {
+					pj.logger.Error(pj.name, zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels the running task and waits for it to return, or for ctx to
+// be done.
+func (pj *PeriodicJob) Stop(ctx context.Context) error {
+	if pj.cancel == nil {
+		return nil
+	}
+
+	pj.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		pj.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type TestPeriodicJobSuite struct {
+	suite.Suite
+}
+
+func TestPeriodicJobTestSuite(t *testing.T) {
+	suite.Run(t, new(TestPeriodicJobSuite))
+}
+
+func (suite *TestPeriodicJobSuite) TestPeriodicJob_StartStop() {
+	suite.Run("runs until stopped", func() {
+		var runs atomic.Int32
+		job := NewPeriodicJob("test", time.Millisecond, zap.NewNop(), func(ctx context.Context) error {
+			runs.Add(1)
+			return errors.New("keeps running on error")
+		})
+
+		job.Start()
+		suite.Eventually(func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+
+		err := job.Stop(context.Background())
+		suite.NoError(err)
+
+		stoppedAt := runs.Load()
+		time.Sleep(10 * time.Millisecond)
+		suite.Equal(stoppedAt, runs.Load())
+	})
+
+	suite.Run("stop times out on a stuck task", func() {
+		var once sync.Once
+		started := make(chan struct{})
+		release := make(chan struct{})
+		job := NewPeriodicJob("test", time.Millisecond, zap.NewNop(), func(ctx context.Context) error {
+			once.Do(func() { close(started) })
+			<-release
+			return nil
+		})
+
+		job.Start()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := job.Stop(ctx)
+		suite.ErrorIs(err, context.DeadlineExceeded)
+		close(release)
+	})
+
+	suite.Run("stop without start", func() {
+		job := NewPeriodicJob("test", time.Millisecond, zap.NewNop(), func(ctx context.Context) error {
+			return nil
+		})
+
+		suite.NoError(job.Stop(context.Background()))
+	})
+}
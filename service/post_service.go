@@ -4,70 +4,335 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/markup"
 	"github.com/elangreza14/assetfindr-test/model"
+	"github.com/elangreza14/assetfindr-test/normalizer"
+	"github.com/pmezard/go-difflib/difflib"
 	"gorm.io/gorm"
 )
 
+const (
+	defaultPostsLimit = 10
+	// publishDuePostsBatch is how many scheduled drafts are published per
+	// transaction.
+	publishDuePostsBatch = 100
+	// includedCommentsLimit is how many comments are included in each post,
+	// the oldest ones.
+	includedCommentsLimit = 20
+	// renderPostsBatch is how many legacy posts are rendered per query.
+	renderPostsBatch = 100
+	// excerptLength is the maximum number of characters of the excerpts.
+	excerptLength = 200
+	// wordsPerMinute is the reading speed the reading times are estimated
+	// with.
+	wordsPerMinute = 200
+	// maxSlugLength is the maximum number of characters of the slugs made
+	// from the titles, before they are made unique.
+	maxSlugLength = 80
+)
+
+// postTransitions lists the statuses a post can move to from each status.
+var postTransitions = map[model.PostStatus][]model.PostStatus{
+	model.PostStatusDraft:     {model.PostStatusPublished},
+	model.PostStatusPublished: {model.PostStatusDraft, model.PostStatusArchived},
+	model.PostStatusArchived:  {model.PostStatusPublished},
+}
+
+// postColumns are the fields of the posts that can be asked for, along with
+// the columns they are read from. The tags and the reactions are relations
+// of the posts, read from no column.
+var postColumns = map[string]string{
+	"id":             "id",
+	"title":          "title",
+	"slug":           "slug",
+	"content":        "content",
+	"content_format": "content_format",
+	"content_html":   "content_html",
+	"excerpt":        "excerpt",
+	"word_count":     "word_count",
+	"reading_time":   "reading_time",
+	"tags":           "",
+	"status":         "status",
+	"reactions":      "",
+	"published_at":   "published_at",
+	"publish_at":     "publish_at",
+	"version":        "version",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
+}
+
+var (
+	errBlankTagLabel = dto.ErrorBadRequest{Message: "tag label cannot be blank"}
+	errPostHasNoTags = dto.ErrorBadRequest{Message: "post must have at least one tag"}
+	errPostModified  = dto.ErrorPreconditionFailed{Message: "post has been modified"}
+	errPublishAtPast = dto.ErrorBadRequest{Message: "publish_at must be in the future"}
+	errNotDraft      = dto.ErrorConflict{Message: "only drafts can be scheduled"}
+	// errUnpublishedPostsAnonymous rejects the anonymous listings of the
+	// posts that are not published.
+	errUnpublishedPostsAnonymous = dto.ErrorUnauthorized{Message: "authentication required to list unpublished posts"}
+)
+
 type (
 	IPostRepository interface {
-		GetPosts(ctx context.Context) ([]model.Post, error)
+		GetPosts(ctx context.Context, filter model.PostFilter) ([]model.Post, int64, error)
+		GetPostsSummary(ctx context.Context, filter model.PostFilter) (*model.PostsSummary, error)
+		SearchPosts(ctx context.Context, query string, limit, offset int) ([]model.PostSearchResult, int64, error)
 		CreatePost(ctx context.Context, req model.Post) error
 		GetPost(ctx context.Context, id int) (*model.Post, error)
+		GetPostBySlug(ctx context.Context, slug string) (*model.Post, error)
 		UpdatePost(ctx context.Context, req model.Post, tagsToBeDeleted ...int) error
+		PatchPost(ctx context.Context, req model.Post, columns []string, tagsToBeDeleted ...int) error
+		UpdatePostStatus(ctx context.Context, req model.Post) error
+		PublishDuePosts(ctx context.Context, now time.Time, limit int) (int64, error)
 		DeletePost(ctx context.Context, req model.Post) error
+		GetTrashedPosts(ctx context.Context, authorID, limit, offset int) ([]model.Post, int64, error)
+		GetTrashedPost(ctx context.Context, id int) (*model.Post, error)
+		RestorePost(ctx context.Context, id int) error
+		PurgePost(ctx context.Context, id int) error
+		PurgeTrashedPosts(ctx context.Context, deletedBefore time.Time) (int64, error)
+		GetUnrenderedPosts(ctx context.Context, afterID, limit int) ([]model.Post, error)
+		UpdateRenderedContent(ctx context.Context, req model.Post) error
+		GetPostRevisions(ctx context.Context, postID, limit, offset int) ([]model.PostRevision, int64, error)
+		GetPostRevision(ctx context.Context, postID, revision int) (*model.PostRevision, error)
 	}
 
 	PostService struct {
 		postRepository IPostRepository
+		cursorSecret   []byte
+		tagNormalizer  normalizer.TagNormalizer
+		trashRetention time.Duration
+		now            func() time.Time
+		// regenerateSlugs makes the slugs of the posts follow their titles.
+		regenerateSlugs bool
 	}
+
+	PostServiceOption func(*PostService)
 )
 
-func NewPostService(postRepository IPostRepository) *PostService {
-	return &PostService{
+// WithCursorSecret sets the key used to sign pagination cursors. Without it a
+// random key is used, so cursors do not survive a restart.
+func WithCursorSecret(secret []byte) PostServiceOption {
+	return func(ps *PostService) {
+		ps.cursorSecret = secret
+	}
+}
+
+// WithPostTagNormalizer sets how tag labels are normalized, they are lower
+// cased by default.
+func WithPostTagNormalizer(tagNormalizer normalizer.TagNormalizer) PostServiceOption {
+	return func(ps *PostService) {
+		ps.tagNormalizer = tagNormalizer
+	}
+}
+
+// WithTrashRetention sets how long deleted posts are kept in the trash before
+// PurgeTrash deletes them for good, 30 days by default.
+func WithTrashRetention(retention time.Duration) PostServiceOption {
+	return func(ps *PostService) {
+		ps.trashRetention = retention
+	}
+}
+
+// WithSlugRegeneration makes the updates changing the title of a post give it
+// a new slug, the slugs are kept by default. The previous slugs keep leading
+// to the post.
+func WithSlugRegeneration() PostServiceOption {
+	return func(ps *PostService) {
+		ps.regenerateSlugs = true
+	}
+}
+
+// WithClock sets the clock used for scheduling and retention, time.Now by
+// default.
+func WithClock(now func() time.Time) PostServiceOption {
+	return func(ps *PostService) {
+		ps.now = now
+	}
+}
+
+func NewPostService(postRepository IPostRepository, opts ...PostServiceOption) *PostService {
+	ps := &PostService{
 		postRepository: postRepository,
+		tagNormalizer:  normalizer.NewTagNormalizer(normalizer.CaseLower),
+		trashRetention: 30 * 24 * time.Hour,
+		now:            time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(ps)
 	}
+
+	if len(ps.cursorSecret) == 0 {
+		ps.cursorSecret = make([]byte, 32)
+		_, _ = rand.Read(ps.cursorSecret)
+	}
+
+	return ps
 }
 
-func (ps *PostService) GetPosts(ctx context.Context) ([]dto.GetPostResponse, error) {
-	posts, err := ps.postRepository.GetPosts(ctx)
+func (ps *PostService) GetPosts(ctx context.Context, req dto.GetPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPostsLimit
+	}
+
+	// one extra post is fetched to know whether a next page exists
+	filter := model.PostFilter{
+		Limit:        limit + 1,
+		Offset:       req.Offset,
+		Tags:         ps.tagKeys(req.Tags),
+		MatchAllTags: req.Match == "all",
+	}
+
+	var err error
+	filter.Status, filter.AuthorID, err = listedPosts(ctx, req.Status, req.Author)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	var fields []string
+	filter.Fields, fields, err = postFields(req.Fields, req.Include)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.Cursor != "" {
+		cursor, err := ps.decodeCursor(req.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		filter.Offset = 0
+		filter.BeforeID = cursor.ID
+	}
+
+	posts, total, err := ps.postRepository.GetPosts(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &dto.Pagination{
+		PerPage: limit,
+		Total:   total,
+	}
+
+	if req.Cursor == "" {
+		pagination.Page = req.Offset/limit + 1
+	}
+
+	if len(posts) > limit {
+		posts = posts[:limit]
+		pagination.NextCursor, err = ps.encodeCursor(postCursor{ID: posts[limit-1].ID})
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	res := make([]dto.GetPostResponse, len(posts))
 	for i, post := range posts {
-		tags := make([]string, len(post.Tags))
-		for j, tag := range post.Tags {
-			tags[j] = tag.Label
+		res[i] = newGetPostResponse(post)
+		res[i].Fields = fields
+		if filter.Fields != nil && filter.Fields.Comments {
+			res[i].Comments = newGetCommentResponses(post.Comments)
 		}
+	}
 
-		res[i] = dto.GetPostResponse{
-			ID:      post.ID,
-			Title:   post.Title,
-			Content: post.Content,
-			Tags:    tags,
+	return res, pagination, nil
+}
+
+// GetPostsSummary summarizes the posts listed by req, regardless of its
+// pagination.
+func (ps *PostService) GetPostsSummary(ctx context.Context, req dto.GetPostsRequest) (*dto.PostsSummary, error) {
+	status, authorID, err := listedPosts(ctx, req.Status, req.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := ps.postRepository.GetPostsSummary(ctx, model.PostFilter{
+		Tags:         ps.tagKeys(req.Tags),
+		MatchAllTags: req.Match == "all",
+		Status:       status,
+		AuthorID:     authorID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PostsSummary{
+		Total:        summary.Total,
+		LastModified: summary.LastUpdatedAt,
+	}, nil
+}
+
+func (ps *PostService) SearchPosts(ctx context.Context, req dto.SearchPostsRequest) ([]dto.SearchPostResponse, *dto.Pagination, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPostsLimit
+	}
+
+	results, total, err := ps.postRepository.SearchPosts(ctx, req.Query, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.SearchPostResponse, len(results))
+	for i, result := range results {
+		res[i] = dto.SearchPostResponse{
+			GetPostResponse: newGetPostResponse(result.Post),
+			Rank:            result.Rank,
+			Snippet:         markup.Highlight(result.Snippet, model.SnippetStartSel, model.SnippetStopSel),
 		}
 	}
 
-	return res, nil
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
 }
 
+// CreatePost creates the post as a draft, scheduled to be published when
+// req.PublishAt is set. The user of ctx is its author.
 func (ps *PostService) CreatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest) error {
-	tags := make([]*model.Tag, len(req.Tags))
-	for i, tag := range req.Tags {
-		tags[i] = &model.Tag{
-			Label: tag,
-		}
+	err := authorize(ctx, auth.PermCreatePost)
+	if err != nil {
+		return err
 	}
 
-	err := ps.postRepository.CreatePost(ctx, model.Post{
-		Title:   req.Title,
-		Content: req.Content,
-		Tags:    tags,
-	})
+	if req.PublishAt != nil && !req.PublishAt.After(ps.now()) {
+		return errPublishAtPast
+	}
+
+	tags, err := normalizeTags(ps.tagNormalizer, req.Tags)
+	if err != nil {
+		return err
+	}
+
+	userID := auth.UserID(ctx)
+	post := model.Post{
+		Title:     req.Title,
+		Slug:      postSlug(req.Title),
+		Tags:      tags,
+		Status:    model.PostStatusDraft,
+		PublishAt: req.PublishAt,
+		UserID:    &userID,
+	}
+
+	err = setContent(&post, contentFormat(req.ContentFormat, ""), req.Content)
+	if err != nil {
+		return err
+	}
+
+	err = ps.postRepository.CreatePost(ctx, post)
 	if err != nil {
 		return err
 	}
@@ -75,96 +340,916 @@ func (ps *PostService) CreatePost(ctx context.Context, req dto.CreateOrUpdatePos
 	return nil
 }
 
+// GetPost gets the post, the posts that are not published are only found by
+// the users who can see them.
 func (ps *PostService) GetPost(ctx context.Context, id int) (*dto.GetPostResponse, error) {
-	post, err := ps.postRepository.GetPost(ctx, id)
+	post, err := ps.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := newGetPostResponse(*post)
+	return &res, nil
+}
+
+// GetPostBySlug gets the post with the slug, or the post which had the slug
+// before. The slug of the response is the current slug of the post.
+func (ps *PostService) GetPostBySlug(ctx context.Context, slug string) (*dto.GetPostResponse, error) {
+	post, err := ps.postRepository.GetPostBySlug(ctx, slug)
+	if err == nil && !canSeePost(ctx, post) {
+		err = gorm.ErrRecordNotFound
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, dto.ErrorNotFound{
 				EntityName: "post",
-				EntityID:   id,
+				EntitySlug: slug,
 			}
 		}
 		return nil, err
 	}
 
-	tags := make([]string, len(post.Tags))
-	for j, tag := range post.Tags {
-		tags[j] = tag.Label
+	res := newGetPostResponse(*post)
+	return &res, nil
+}
+
+// UpdatePost replaces the post, as long as version is its current version.
+// A zero version matches any version. Only drafts can be scheduled.
+func (ps *PostService) UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id, version int) error {
+	post, err := ps.getPostToChange(ctx, id, version, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return err
 	}
 
-	return &dto.GetPostResponse{
-		ID:      post.ID,
-		Title:   post.Title,
-		Content: post.Content,
-		Tags:    tags,
-	}, nil
+	if req.PublishAt != nil {
+		if post.Status != model.PostStatusDraft {
+			return errNotDraft
+		}
+
+		if !req.PublishAt.After(ps.now()) {
+			return errPublishAtPast
+		}
+	}
+
+	return ps.replacePost(ctx, post, req)
 }
 
-func (ps *PostService) UpdatePost(ctx context.Context, req dto.CreateOrUpdatePostRequest, id int) error {
-	post, err := ps.postRepository.GetPost(ctx, id)
+// replacePost replaces the post with req, which is expected to be validated
+// against the post already.
+func (ps *PostService) replacePost(ctx context.Context, post *model.Post, req dto.CreateOrUpdatePostRequest) error {
+	newTagsToBeSave, err := normalizeTags(ps.tagNormalizer, req.Tags)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.ErrorNotFound{
-				EntityName: "post",
-				EntityID:   id,
-			}
+		return err
+	}
+
+	prevTags := make(map[string]*model.Tag)
+	for _, tag := range post.Tags {
+		prevTags[normalizer.Key(tag.Label)] = tag
+	}
+
+	for _, reqTag := range newTagsToBeSave {
+		key := normalizer.Key(reqTag.Label)
+		if _, ok := prevTags[key]; !ok {
+			continue
 		}
+
+		delete(prevTags, key)
+	}
+
+	prevTagsIDToBeDelete := make([]int, 0)
+	for _, tag := range prevTags {
+		prevTagsIDToBeDelete = append(prevTagsIDToBeDelete, tag.ID)
+	}
+
+	update := model.Post{
+		ID:        post.ID,
+		Title:     req.Title,
+		Slug:      ps.newSlug(post, req.Title),
+		Tags:      newTagsToBeSave,
+		PublishAt: req.PublishAt,
+		Version:   post.Version,
+	}
+
+	err = setContent(&update, contentFormat(req.ContentFormat, post.ContentFormat), req.Content)
+	if err != nil {
 		return err
 	}
 
-	newTagsToBeSave := make([]*model.Tag, len(req.Tags))
-	for i, tag := range req.Tags {
-		newTagsToBeSave[i] = &model.Tag{
-			Label: tag,
+	err = ps.postRepository.UpdatePost(ctx, update, prevTagsIDToBeDelete...)
+	if err != nil {
+		if errors.Is(err, model.ErrVersionConflict) {
+			return errPostModified
 		}
+		return err
 	}
 
-	prevTags := make(map[string]*model.Tag)
+	return nil
+}
+
+// PatchPost applies a merge patch to the post, as long as version is its
+// current version. The columns are only updated when they change, and nothing
+// is written when the patch changes nothing.
+func (ps *PostService) PatchPost(ctx context.Context, req dto.PatchPostRequest, id, version int) error {
+	post, err := ps.getPostToChange(ctx, id, version, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return err
+	}
+
+	patch := model.Post{ID: id, Version: post.Version}
+	columns := []string{}
+	if req.Title != nil && *req.Title != post.Title {
+		patch.Title = *req.Title
+		patch.Slug = ps.newSlug(post, *req.Title)
+		columns = append(columns, "title")
+	}
+
+	// the html and its metadata are derived again whenever the content or
+	// its format change
+	content, format := post.Content, contentFormat("", post.ContentFormat)
+	rerender := false
+	if req.Content != nil && *req.Content != post.Content {
+		content = *req.Content
+		columns = append(columns, "content")
+		rerender = true
+	}
+
+	if req.ContentFormat != nil && model.ContentFormat(*req.ContentFormat) != format {
+		format = model.ContentFormat(*req.ContentFormat)
+		columns = append(columns, "content_format")
+		rerender = true
+	}
+
+	if rerender {
+		err = setContent(&patch, format, content)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, "content_html", "excerpt", "word_count", "reading_time")
+	}
+
+	tags := post.Tags
+	if req.Tags != nil {
+		tags, err = normalizeTags(ps.tagNormalizer, *req.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	addTags, err := normalizeTags(ps.tagNormalizer, req.AddTags)
+	if err != nil {
+		return err
+	}
+
+	removeTags, err := normalizeTags(ps.tagNormalizer, req.RemoveTags)
+	if err != nil {
+		return err
+	}
+
+	tags = patchTags(tags, addTags, removeTags)
+	if len(tags) == 0 {
+		return errPostHasNoTags
+	}
+
+	prevTags := make(map[string]*model.Tag, len(post.Tags))
 	for _, tag := range post.Tags {
-		prevTags[tag.Label] = tag
+		prevTags[normalizer.Key(tag.Label)] = tag
 	}
 
-	for _, reqTag := range req.Tags {
-		if _, ok := prevTags[reqTag]; !ok {
+	for _, tag := range tags {
+		key := normalizer.Key(tag.Label)
+		if _, ok := prevTags[key]; ok {
+			delete(prevTags, key)
 			continue
 		}
 
-		delete(prevTags, reqTag)
+		patch.Tags = append(patch.Tags, tag)
 	}
 
-	prevTagsIDToBeDelete := make([]int, 0)
+	prevTagsIDToBeDelete := make([]int, 0, len(prevTags))
 	for _, tag := range prevTags {
 		prevTagsIDToBeDelete = append(prevTagsIDToBeDelete, tag.ID)
 	}
 
-	err = ps.postRepository.UpdatePost(ctx, model.Post{
-		ID:      id,
-		Title:   req.Title,
-		Content: req.Content,
-		Tags:    newTagsToBeSave,
-	}, prevTagsIDToBeDelete...)
+	if len(columns) == 0 && len(patch.Tags) == 0 && len(prevTagsIDToBeDelete) == 0 {
+		return nil
+	}
+
+	err = ps.postRepository.PatchPost(ctx, patch, columns, prevTagsIDToBeDelete...)
 	if err != nil {
+		if errors.Is(err, model.ErrVersionConflict) {
+			return errPostModified
+		}
 		return err
 	}
 
 	return nil
 }
 
-func (ps *PostService) DeletePost(ctx context.Context, id int) error {
+// UpdatePostStatus moves the post to the status, as long as the transition is
+// allowed and version is its current version. The publication time is set
+// the first time the post is published, and the schedule of the post is
+// cancelled.
+func (ps *PostService) UpdatePostStatus(ctx context.Context, status string, id, version int) error {
+	post, err := ps.getPostToChange(ctx, id, version, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return err
+	}
+
+	to := model.PostStatus(status)
+	if !slices.Contains(postTransitions[post.Status], to) {
+		return dto.ErrorConflict{Message: fmt.Sprintf("cannot move post from %s to %s", post.Status, to)}
+	}
+
+	publishedAt := post.PublishedAt
+	if to == model.PostStatusPublished && publishedAt == nil {
+		now := ps.now()
+		publishedAt = &now
+	}
+
+	err = ps.postRepository.UpdatePostStatus(ctx, model.Post{
+		ID:          id,
+		Status:      to,
+		PublishedAt: publishedAt,
+		Version:     post.Version,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrVersionConflict) {
+			return errPostModified
+		}
+		return err
+	}
+
+	return nil
+}
+
+// PublishScheduledPosts publishes the drafts whose schedule is due, batch by
+// batch until none is left.
+func (ps *PostService) PublishScheduledPosts(ctx context.Context) error {
+	now := ps.now()
+	for {
+		published, err := ps.postRepository.PublishDuePosts(ctx, now, publishDuePostsBatch)
+		if err != nil {
+			return err
+		}
+
+		if published < publishDuePostsBatch {
+			return nil
+		}
+	}
+}
+
+// RenderLegacyPosts stores the html, the excerpt, the word count and the
+// reading time of the posts written before they were stored, batch by batch
+// until none is left, and returns how many were rendered.
+func (ps *PostService) RenderLegacyPosts(ctx context.Context) (int, error) {
+	rendered, afterID := 0, 0
+	for {
+		posts, err := ps.postRepository.GetUnrenderedPosts(ctx, afterID, renderPostsBatch)
+		if err != nil {
+			return rendered, err
+		}
+
+		for _, post := range posts {
+			err = setContent(&post, contentFormat("", post.ContentFormat), post.Content)
+			if err != nil {
+				return rendered, err
+			}
+
+			err = ps.postRepository.UpdateRenderedContent(ctx, post)
+			if err != nil {
+				return rendered, err
+			}
+
+			rendered++
+			afterID = post.ID
+		}
+
+		if len(posts) < renderPostsBatch {
+			return rendered, nil
+		}
+	}
+}
+
+// DeletePost moves the post to the trash, as long as version is its current
+// version.
+func (ps *PostService) DeletePost(ctx context.Context, id, version int) error {
+	post, err := ps.getPostToChange(ctx, id, version, auth.PermDeletePost, auth.PermDeleteAnyPost)
+	if err != nil {
+		return err
+	}
+
+	err = ps.postRepository.DeletePost(ctx, *post)
+	if err != nil {
+		if errors.Is(err, model.ErrVersionConflict) {
+			return errPostModified
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetTrashedPosts lists the deleted posts the user can restore, the users
+// seeing any trash list the deleted posts of everyone.
+func (ps *PostService) GetTrashedPosts(ctx context.Context, req dto.GetTrashedPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
+	err := authorize(ctx, auth.PermDeletePost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authorID := 0
+	principal, _ := auth.PrincipalFrom(ctx)
+	if !principal.CanSeeAnyTrash() {
+		authorID = principal.UserID
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPostsLimit
+	}
+
+	posts, total, err := ps.postRepository.GetTrashedPosts(ctx, authorID, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetPostResponse, len(posts))
+	for i, post := range posts {
+		res[i] = newGetPostResponse(post)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// RestorePost moves the post back from the trash.
+func (ps *PostService) RestorePost(ctx context.Context, id int) error {
+	post, err := ps.getTrashedPost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = authorizePost(ctx, post, auth.PermDeletePost, auth.PermDeleteAnyPost)
+	if err != nil {
+		return err
+	}
+
+	err = ps.postRepository.RestorePost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PurgePost deletes for good a post of the trash.
+func (ps *PostService) PurgePost(ctx context.Context, id int) error {
+	err := authorize(ctx, auth.PermPurgeTrash)
+	if err != nil {
+		return err
+	}
+
+	_, err = ps.getTrashedPost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = ps.postRepository.PurgePost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PurgeTrash deletes for good the posts kept in the trash for longer than the
+// retention.
+func (ps *PostService) PurgeTrash(ctx context.Context) error {
+	_, err := ps.postRepository.PurgeTrashedPosts(ctx, ps.now().Add(-ps.trashRetention))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ps *PostService) getTrashedPost(ctx context.Context, id int) (*model.Post, error) {
+	post, err := ps.postRepository.GetTrashedPost(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "deleted post",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// GetPostRevisions lists the previous revisions of the post, the latest first.
+func (ps *PostService) GetPostRevisions(ctx context.Context, id int, req dto.GetPostRevisionsRequest) ([]dto.GetPostRevisionResponse, *dto.Pagination, error) {
+	_, err := ps.getPost(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPostsLimit
+	}
+
+	revisions, total, err := ps.postRepository.GetPostRevisions(ctx, id, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetPostRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		res[i] = newGetPostRevisionResponse(revision)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// GetPostRevision gets the post as it was at the revision, which can also be
+// its current version.
+func (ps *PostService) GetPostRevision(ctx context.Context, id, rev int) (*dto.GetPostRevisionResponse, error) {
+	post, err := ps.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := ps.getPostRevision(ctx, post, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	res := newGetPostRevisionResponse(*revision)
+	return &res, nil
+}
+
+// DiffPostRevisions compares two revisions of the post with a unified diff.
+func (ps *PostService) DiffPostRevisions(ctx context.Context, id int, req dto.DiffPostRevisionsRequest) (*dto.PostRevisionDiffResponse, error) {
+	post, err := ps.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	to := req.To
+	if to == 0 {
+		to = post.Version
+	}
+
+	fromRevision, err := ps.getPostRevision(ctx, post, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := ps.getPostRevision(ctx, post, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(*fromRevision)),
+		B:        difflib.SplitLines(revisionText(*toRevision)),
+		FromFile: fmt.Sprintf("revision %d", req.From),
+		ToFile:   fmt.Sprintf("revision %d", to),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PostRevisionDiffResponse{
+		From: req.From,
+		To:   to,
+		Diff: diff,
+	}, nil
+}
+
+// RestorePostRevision updates the post back to the revision, as long as
+// version is its current version. The restore is an update like any other, so
+// the post is saved as a new revision beforehand. The schedule of the post is
+// kept as is, even when it is already due, since the restore does not change
+// it.
+func (ps *PostService) RestorePostRevision(ctx context.Context, id, rev, version int) error {
+	post, err := ps.getPostToChange(ctx, id, version, auth.PermEditPost, auth.PermEditAnyPost)
+	if err != nil {
+		return err
+	}
+
+	revision, err := ps.getPostRevision(ctx, post, rev)
+	if err != nil {
+		return err
+	}
+
+	return ps.replacePost(ctx, post, dto.CreateOrUpdatePostRequest{
+		Title:         revision.Title,
+		Content:       revision.Content,
+		ContentFormat: string(contentFormat("", revision.ContentFormat)),
+		Tags:          revision.Tags,
+		PublishAt:     post.PublishAt,
+	})
+}
+
+// getPostRevision gets the revision of the post, the current version of the
+// post being its latest revision.
+func (ps *PostService) getPostRevision(ctx context.Context, post *model.Post, rev int) (*model.PostRevision, error) {
+	if rev == post.Version {
+		tags := make([]string, len(post.Tags))
+		for i, tag := range post.Tags {
+			tags[i] = tag.Label
+		}
+
+		return &model.PostRevision{
+			PostID:        post.ID,
+			Revision:      post.Version,
+			Title:         post.Title,
+			Content:       post.Content,
+			ContentFormat: post.ContentFormat,
+			Tags:          tags,
+			CreatedAt:     post.UpdatedAt,
+		}, nil
+	}
+
+	revision, err := ps.postRepository.GetPostRevision(ctx, post.ID, rev)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "revision",
+				EntityID:   rev,
+			}
+		}
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// getPost gets the post, failing with a not found error when it does not
+// exist or the user of ctx cannot see it.
+func (ps *PostService) getPost(ctx context.Context, id int) (*model.Post, error) {
 	post, err := ps.postRepository.GetPost(ctx, id)
+	if err == nil && !canSeePost(ctx, post) {
+		err = gorm.ErrRecordNotFound
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return dto.ErrorNotFound{
+			return nil, dto.ErrorNotFound{
 				EntityName: "post",
 				EntityID:   id,
 			}
 		}
-		return err
+		return nil, err
 	}
-	err = ps.postRepository.DeletePost(ctx, *post)
+
+	return post, nil
+}
+
+// getPostToChange gets the post for the user of ctx to change it, with the
+// own permission for their posts and the any permission for the posts of
+// other users. It fails when version is neither zero nor the current version
+// of the post.
+func (ps *PostService) getPostToChange(ctx context.Context, id, version int, own, any auth.Permission) (*model.Post, error) {
+	post, err := ps.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizePost(ctx, post, own, any)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != 0 && version != post.Version {
+		return nil, errPostModified
+	}
+
+	return post, nil
+}
+
+func newGetPostResponse(post model.Post) dto.GetPostResponse {
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = tag.Label
+	}
+
+	res := dto.GetPostResponse{
+		ID:            post.ID,
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Content:       post.Content,
+		ContentFormat: string(contentFormat("", post.ContentFormat)),
+		ContentHTML:   post.ContentHTML,
+		Excerpt:       post.Excerpt,
+		WordCount:     post.WordCount,
+		ReadingTime:   post.ReadingTime,
+		Tags:          tags,
+		Status:        string(post.Status),
+		PublishedAt:   post.PublishedAt,
+		PublishAt:     post.PublishAt,
+		Version:       post.Version,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+	if post.User != nil {
+		res.Author = &dto.Author{
+			ID:   post.User.ID,
+			Name: post.User.Name,
+		}
+	}
+	for _, reactionCount := range post.ReactionCounts {
+		if reactionCount.Count == 0 {
+			continue
+		}
+		if res.Reactions == nil {
+			res.Reactions = map[string]int{}
+		}
+		res.Reactions[string(reactionCount.Kind)] = reactionCount.Count
+	}
+	if post.DeletedAt.Valid {
+		res.DeletedAt = &post.DeletedAt.Time
+	}
+
+	return res
+}
+
+func newGetPostRevisionResponse(revision model.PostRevision) dto.GetPostRevisionResponse {
+	return dto.GetPostRevisionResponse{
+		Revision:      revision.Revision,
+		Title:         revision.Title,
+		Content:       revision.Content,
+		ContentFormat: string(contentFormat("", revision.ContentFormat)),
+		Tags:          revision.Tags,
+		CreatedAt:     revision.CreatedAt,
+	}
+}
+
+// revisionText renders the revision as the text compared by the diffs.
+func revisionText(revision model.PostRevision) string {
+	return fmt.Sprintf("title: %s\ntags: %s\n\n%s",
+		revision.Title,
+		strings.Join(revision.Tags, ", "),
+		revision.Content)
+}
+
+// contentFormat is the format asked for, or the current format when none is
+// asked for. Posts without a format are plain text.
+func contentFormat(format string, current model.ContentFormat) model.ContentFormat {
+	if format != "" {
+		return model.ContentFormat(format)
+	}
+
+	if current != "" {
+		return current
+	}
+
+	return model.ContentFormatPlain
+}
+
+// renderContent renders the content in its format to sanitized HTML.
+func renderContent(format model.ContentFormat, content string) (string, error) {
+	if format == model.ContentFormatMarkdown {
+		return markup.Markdown(content)
+	}
+
+	return markup.Plain(content), nil
+}
+
+// setContent sets the content of the post in the format, along with the html
+// rendered from it and the excerpt, word count and reading time derived from
+// the text of the html.
+func setContent(post *model.Post, format model.ContentFormat, content string) error {
+	contentHTML, err := renderContent(format, content)
 	if err != nil {
 		return err
 	}
 
+	text := markup.Text(contentHTML)
+	post.Content = content
+	post.ContentFormat = format
+	post.ContentHTML = contentHTML
+	post.Excerpt = markup.Excerpt(text, excerptLength)
+	post.WordCount = len(strings.Fields(text))
+	post.ReadingTime = (post.WordCount + wordsPerMinute - 1) / wordsPerMinute
 	return nil
 }
+
+// postFields picks what is loaded of the posts for the comma separated fields
+// and resources to include asked for, along with the fields of the
+// responses. The posts are loaded whole, with their author, when neither is
+// asked for, or along with the resources included when only they are.
+// Otherwise only the fields asked for are loaded, and the author is only
+// included when asked for.
+func postFields(fields, include string) (*model.PostFields, []string, error) {
+	if fields == "" && include == "" {
+		return nil, nil, nil
+	}
+
+	res := &model.PostFields{Tags: true, Author: true, Reactions: true}
+	var names []string
+	if fields != "" {
+		res = &model.PostFields{Columns: []string{"id"}}
+		names = []string{"id"}
+		for _, name := range strings.Split(fields, ",") {
+			name = strings.TrimSpace(name)
+			column, ok := postColumns[name]
+			if !ok {
+				return nil, nil, dto.ErrorBadRequest{Message: fmt.Sprintf("unknown field %s", name)}
+			}
+
+			if slices.Contains(names, name) {
+				continue
+			}
+
+			names = append(names, name)
+			switch name {
+			case "tags":
+				res.Tags = true
+			case "reactions":
+				res.Reactions = true
+			default:
+				if column != "id" {
+					res.Columns = append(res.Columns, column)
+				}
+			}
+		}
+	}
+
+	if include != "" {
+		for _, name := range strings.Split(include, ",") {
+			name = strings.TrimSpace(name)
+			switch name {
+			case "author":
+				res.Author = true
+				if len(res.Columns) > 0 {
+					res.Columns = append(res.Columns, "user_id")
+				}
+			case "comments":
+				res.Comments = true
+				res.CommentsLimit = includedCommentsLimit
+			default:
+				return nil, nil, dto.ErrorBadRequest{Message: fmt.Sprintf("unknown include %s", name)}
+			}
+
+			if names != nil && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return res, names, nil
+}
+
+// newSlug is the slug made from title for the update of the post, or empty
+// when the post keeps its slug. The posts created before slugs existed are
+// always given one.
+func (ps *PostService) newSlug(post *model.Post, title string) string {
+	slug := postSlug(title)
+	if post.Slug == "" || ps.regenerateSlugs && slug != postSlug(post.Title) {
+		return slug
+	}
+
+	return ""
+}
+
+// postSlug makes the slug of a post from its title, cut at a dash so that it
+// is at most maxSlugLength characters long. The titles without any letter or
+// digit make the slug post.
+func postSlug(title string) string {
+	slug := []rune(normalizer.Slug(title))
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength+1]
+		cut := maxSlugLength
+		for cut > 0 && slug[cut] != '-' {
+			cut--
+		}
+		if cut == 0 {
+			cut = maxSlugLength
+		}
+		slug = slug[:cut]
+	}
+
+	if len(slug) == 0 {
+		return "post"
+	}
+
+	return string(slug)
+}
+
+// postStatus is the status of the posts listed, published unless asked
+// otherwise.
+func postStatus(status string) model.PostStatus {
+	if status == "" {
+		return model.PostStatusPublished
+	}
+
+	return model.PostStatus(status)
+}
+
+// listedPosts narrows the listing of the posts in the status down to the posts
+// the user of ctx can see, returning the status and the author listed. The
+// posts that are not published are only listed for their author, unless the
+// user is allowed to edit any post, and never anonymously.
+func listedPosts(ctx context.Context, status string, authorID int) (model.PostStatus, int, error) {
+	listed := postStatus(status)
+	if listed == model.PostStatusPublished {
+		return listed, authorID, nil
+	}
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return "", 0, errUnpublishedPostsAnonymous
+	}
+
+	if principal.Can(auth.PermEditAnyPost) {
+		return listed, authorID, nil
+	}
+
+	if authorID != 0 && authorID != principal.UserID {
+		return "", 0, errPermissionRequired(auth.PermEditAnyPost)
+	}
+
+	return listed, principal.UserID, nil
+}
+
+// tagKeys normalizes labels into unique keys comparable with the lower case
+// labels of the tags table.
+func (ps *PostService) tagKeys(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(labels))
+	res := make([]string, 0, len(labels))
+	for _, label := range labels {
+		key := normalizer.Key(ps.tagNormalizer.Label(label))
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		res = append(res, key)
+	}
+
+	return res
+}
+
+// patchTags adds then removes tags from the normalized tags, comparing them by
+// their keys.
+func patchTags(tags, addTags, removeTags []*model.Tag) []*model.Tag {
+	removed := make(map[string]bool, len(removeTags))
+	for _, tag := range removeTags {
+		removed[normalizer.Key(tag.Label)] = true
+	}
+
+	seen := make(map[string]bool, len(tags)+len(addTags))
+	res := make([]*model.Tag, 0, len(tags)+len(addTags))
+	for _, tag := range append(tags[:len(tags):len(tags)], addTags...) {
+		key := normalizer.Key(tag.Label)
+		if seen[key] || removed[key] {
+			continue
+		}
+
+		seen[key] = true
+		res = append(res, tag)
+	}
+
+	return res
+}
+
+// normalizeTags builds tags out of labels, dropping the labels that are the
+// same as a previous one once normalized.
+func normalizeTags(tagNormalizer normalizer.TagNormalizer, labels []string) ([]*model.Tag, error) {
+	seen := make(map[string]bool, len(labels))
+	res := make([]*model.Tag, 0, len(labels))
+	for _, raw := range labels {
+		label := tagNormalizer.Label(raw)
+		if label == "" {
+			return nil, errBlankTagLabel
+		}
+
+		key := normalizer.Key(label)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		res = append(res, &model.Tag{
+			Label: label,
+			Slug:  normalizer.Slug(label),
+		})
+	}
+
+	return res, nil
+}
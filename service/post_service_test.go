@@ -3,8 +3,11 @@ package service_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/elangreza14/assetfindr-test/auth"
 	"github.com/elangreza14/assetfindr-test/dto"
 	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
 	"github.com/elangreza14/assetfindr-test/model"
@@ -20,7 +23,9 @@ type TestPostServiceSuite struct {
 	MockPostRepo      *gomockService.MockIPostRepository
 	MockCreatePostReq dto.CreateOrUpdatePostRequest
 	Cs                *PostService
-	Ctrl              *gomock.Controller
+	// Ctx is made by an admin, allowed to change any post.
+	Ctx  context.Context
+	Ctrl *gomock.Controller
 }
 
 func (suite *TestPostServiceSuite) SetupSuite() {
@@ -32,6 +37,7 @@ func (suite *TestPostServiceSuite) SetupSuite() {
 		Tags:    []string{"test1", "test2"},
 	}
 	suite.Cs = NewPostService(suite.MockPostRepo)
+	suite.Ctx = auth.WithPrincipal(context.Background(), auth.Principal{UserID: 1, Role: auth.RoleAdmin})
 
 }
 
@@ -47,7 +53,7 @@ func (suite *TestPostServiceSuite) TestPostService_CreatePost() {
 	suite.Run("error when create", func() {
 		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(errors.New("err from db"))
 
-		err := suite.Cs.CreatePost(context.Background(), suite.MockCreatePostReq)
+		err := suite.Cs.CreatePost(suite.Ctx, suite.MockCreatePostReq)
 		suite.Error(err)
 		suite.Equal(err.Error(), "err from db")
 	})
@@ -55,7 +61,110 @@ func (suite *TestPostServiceSuite) TestPostService_CreatePost() {
 	suite.Run("success", func() {
 		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).Return(nil)
 
-		err := suite.Cs.CreatePost(context.Background(), suite.MockCreatePostReq)
+		err := suite.Cs.CreatePost(suite.Ctx, suite.MockCreatePostReq)
+		suite.NoError(err)
+	})
+
+	suite.Run("error blank tag", func() {
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   "test",
+			Content: "test",
+			Tags:    []string{"go", " "},
+		})
+		suite.Equal(dto.ErrorBadRequest{Message: "tag label cannot be blank"}, err)
+	})
+
+	suite.Run("success with duplicated tags", func() {
+		userID := 1
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), model.Post{
+			Title:         "test",
+			Slug:          "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags: []*model.Tag{
+				{Label: "go", Slug: "go"},
+				{Label: "c++", Slug: "c"},
+			},
+			UserID: &userID,
+			Status: model.PostStatusDraft,
+		}).Return(nil)
+
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   "test",
+			Content: "test",
+			Tags:    []string{"Go", " go ", "C++", "GO"},
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("success derives the metadata", func() {
+		content := "# Title\n\n" + strings.Repeat("word ", 449)
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req model.Post) error {
+				suite.Equal(450, req.WordCount)
+				suite.Equal(3, req.ReadingTime)
+				suite.Equal("Title"+strings.Repeat(" word", 39)+"…", req.Excerpt)
+				return nil
+			})
+
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:         "test",
+			Content:       content,
+			ContentFormat: "markdown",
+			Tags:          []string{"go"},
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("success slug is cut at a dash", func() {
+		title := strings.Repeat("abcdefghi ", 10)
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req model.Post) error {
+				suite.Equal(strings.TrimSuffix(strings.Repeat("abcdefghi-", 8), "-"), req.Slug)
+				return nil
+			})
+
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   title,
+			Content: "test",
+			Tags:    []string{"go"},
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("success title without letters", func() {
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req model.Post) error {
+				suite.Equal("post", req.Slug)
+				return nil
+			})
+
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   "!!!",
+			Content: "test",
+			Tags:    []string{"go"},
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("success markdown is sanitized", func() {
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req model.Post) error {
+				suite.Equal(model.ContentFormatMarkdown, req.ContentFormat)
+				suite.Equal("<p><strong>hi</strong> </p>\n", req.ContentHTML)
+				return nil
+			})
+
+		err := suite.Cs.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:         "test",
+			Content:       "**hi** <script>alert(1)</script>",
+			ContentFormat: "markdown",
+			Tags:          []string{"go"},
+		})
 		suite.NoError(err)
 	})
 }
@@ -64,7 +173,7 @@ func (suite *TestPostServiceSuite) TestPostService_DeletePost() {
 	suite.Run("error when get post", func() {
 		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(nil, errors.New("err from db"))
 
-		err := suite.Cs.DeletePost(context.Background(), 1)
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "err from db")
 	})
@@ -72,7 +181,7 @@ func (suite *TestPostServiceSuite) TestPostService_DeletePost() {
 	suite.Run("error not found when get post", func() {
 		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
 
-		err := suite.Cs.DeletePost(context.Background(), 1)
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "cannot find post with id 1")
 	})
@@ -92,7 +201,7 @@ func (suite *TestPostServiceSuite) TestPostService_DeletePost() {
 		}, nil)
 		suite.MockPostRepo.EXPECT().DeletePost(gomock.Any(), gomock.Any()).Return(errors.New("error delete"))
 
-		err := suite.Cs.DeletePost(context.Background(), 1)
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "error delete")
 	})
@@ -109,16 +218,51 @@ func (suite *TestPostServiceSuite) TestPostService_DeletePost() {
 		}, nil)
 		suite.MockPostRepo.EXPECT().DeletePost(gomock.Any(), gomock.Any()).Return(nil)
 
-		err := suite.Cs.DeletePost(context.Background(), 1)
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 0)
 		suite.NoError(err)
 	})
+
+	suite.Run("error version mismatch", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Version: 3}, nil)
+
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 2)
+		suite.ErrorIs(err, dto.ErrorPreconditionFailed{Message: "post has been modified"})
+	})
+
+	suite.Run("error version conflict", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Version: 3}, nil)
+		suite.MockPostRepo.EXPECT().DeletePost(gomock.Any(), model.Post{ID: 1, Version: 3}).Return(model.ErrVersionConflict)
+
+		err := suite.Cs.DeletePost(suite.Ctx, 1, 3)
+		suite.ErrorIs(err, dto.ErrorPreconditionFailed{Message: "post has been modified"})
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_GetPostBySlug() {
+	suite.Run("error not found", func() {
+		suite.MockPostRepo.EXPECT().GetPostBySlug(gomock.Any(), "test").Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := suite.Cs.GetPostBySlug(suite.Ctx, "test")
+		suite.Nil(res)
+		suite.Equal(dto.ErrorNotFound{EntityName: "post", EntitySlug: "test"}, err)
+		suite.Equal("cannot find post with slug test", err.Error())
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPostBySlug(gomock.Any(), "old-test").Return(&model.Post{ID: 1, Title: "test", Slug: "test"}, nil)
+
+		res, err := suite.Cs.GetPostBySlug(suite.Ctx, "old-test")
+		suite.NoError(err)
+		suite.Equal(1, res.ID)
+		suite.Equal("test", res.Slug)
+	})
 }
 
 func (suite *TestPostServiceSuite) TestPostService_UpdatePost() {
 	suite.Run("error when get post", func() {
 		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(nil, errors.New("err from db"))
 
-		err := suite.Cs.UpdatePost(context.Background(), suite.MockCreatePostReq, 1)
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "err from db")
 	})
@@ -126,7 +270,7 @@ func (suite *TestPostServiceSuite) TestPostService_UpdatePost() {
 	suite.Run("error not found when get post", func() {
 		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
 
-		err := suite.Cs.UpdatePost(context.Background(), suite.MockCreatePostReq, 1)
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "cannot find post with id 1")
 	})
@@ -149,7 +293,7 @@ func (suite *TestPostServiceSuite) TestPostService_UpdatePost() {
 		}, nil)
 		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error update"))
 
-		err := suite.Cs.UpdatePost(context.Background(), suite.MockCreatePostReq, 1)
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 0)
 		suite.Error(err)
 		suite.Equal(err.Error(), "error update")
 	})
@@ -169,7 +313,498 @@ func (suite *TestPostServiceSuite) TestPostService_UpdatePost() {
 		}, nil)
 		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
-		err := suite.Cs.UpdatePost(context.Background(), suite.MockCreatePostReq, 1)
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success keeps tags differing by case", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(&model.Post{
+			ID:      1,
+			Title:   "test",
+			Slug:    "test",
+			Content: "test",
+			Tags: []*model.Tag{
+				{ID: 1, Label: "go"},
+				{ID: 2, Label: "sql"},
+			},
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "go", Slug: "go"}},
+		}, 2).Return(nil)
+
+		err := suite.Cs.UpdatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   "test",
+			Content: "test",
+			Tags:    []string{"GO"},
+		}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success matching version", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{
+			ID:      1,
+			Title:   "test",
+			Slug:    "test",
+			Content: "test",
+			Tags:    []*model.Tag{{ID: 1, Label: "test1"}, {ID: 2, Label: "test2"}},
+			Version: 4,
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "test1", Slug: "test1"}, {Label: "test2", Slug: "test2"}},
+			Version:       4,
+		}).Return(nil)
+
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 4)
+		suite.NoError(err)
+	})
+
+	suite.Run("error version mismatch", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Version: 4}, nil)
+
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 3)
+		suite.ErrorIs(err, dto.ErrorPreconditionFailed{Message: "post has been modified"})
+	})
+
+	suite.Run("error version conflict", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Version: 4}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(model.ErrVersionConflict)
+
+		err := suite.Cs.UpdatePost(suite.Ctx, suite.MockCreatePostReq, 1, 4)
+		suite.ErrorIs(err, dto.ErrorPreconditionFailed{Message: "post has been modified"})
+	})
+
+	suite.Run("success keeps the format", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{
+			ID:            1,
+			ContentFormat: model.ContentFormatMarkdown,
+			Tags:          []*model.Tag{{ID: 1, Label: "go"}},
+			Version:       2,
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "test",
+			Slug:          "test",
+			Content:       "*test*",
+			ContentFormat: model.ContentFormatMarkdown,
+			ContentHTML:   "<p><em>test</em></p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "go", Slug: "go"}},
+			Version:       2,
+		}).Return(nil)
+
+		err := suite.Cs.UpdatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:   "test",
+			Content: "*test*",
+			Tags:    []string{"go"},
+		}, 1, 2)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_PatchPost() {
+	title := "new title"
+	post := func() *model.Post {
+		return &model.Post{
+			ID:      1,
+			Title:   "test",
+			Slug:    "test",
+			Content: "test",
+			Tags: []*model.Tag{
+				{ID: 1, Label: "go"},
+				{ID: 2, Label: "sql"},
+			},
+		}
+	}
+
+	suite.Run("error not found when get post", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find post with id 1")
+	})
+
+	suite.Run("success only title", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:    1,
+			Title: "new title",
+		}, []string{"title"}).Return(nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success title gives a slug to the posts without one", func() {
+		legacy := post()
+		legacy.Slug = ""
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(legacy, nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:    1,
+			Title: "new title",
+			Slug:  "new-title",
+		}, []string{"title"}).Return(nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success title regenerates the slug", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:    1,
+			Title: "new title",
+			Slug:  "new-title",
+		}, []string{"title"}).Return(nil)
+
+		err := NewPostService(suite.MockPostRepo, WithSlugRegeneration()).
+			PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success title with the same slug keeps the slug", func() {
+		sameSlug := "Test!"
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:    1,
+			Title: "Test!",
+		}, []string{"title"}).Return(nil)
+
+		err := NewPostService(suite.MockPostRepo, WithSlugRegeneration()).
+			PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &sameSlug}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success add and remove tags", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:   1,
+			Tags: []*model.Tag{{Label: "docker", Slug: "docker"}},
+		}, []string{}, 2).Return(nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{
+			AddTags:    []string{"Docker", "GO"},
+			RemoveTags: []string{"SQL"},
+		}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success replace tags", func() {
+		tags := []string{"sql", "docker"}
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:   1,
+			Tags: []*model.Tag{{Label: "docker", Slug: "docker"}},
+		}, []string{}, 1).Return(nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Tags: &tags}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success format renders the content again", func() {
+		format := "markdown"
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), model.Post{
+			ID:            1,
+			Content:       "test",
+			ContentFormat: model.ContentFormatMarkdown,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+		}, []string{"content_format", "content_html", "excerpt", "word_count", "reading_time"}).Return(nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{ContentFormat: &format}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("success without changes", func() {
+		unchanged := "test"
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{
+			Content: &unchanged,
+			AddTags: []string{"Go"},
+		}, 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("error removing every tag", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{
+			RemoveTags: []string{"go", "sql"},
+		}, 1, 0)
+		suite.Error(err)
+		suite.Equal(err.Error(), "post must have at least one tag")
+	})
+
+	suite.Run("error blank tag", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{
+			AddTags: []string{"  "},
+		}, 1, 0)
+		suite.Error(err)
+		suite.Equal(err.Error(), "tag label cannot be blank")
+	})
+
+	suite.Run("error version conflict", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.ErrVersionConflict)
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.ErrorIs(err, dto.ErrorPreconditionFailed{Message: "post has been modified"})
+	})
+
+	suite.Run("error patch post", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post(), nil)
+		suite.MockPostRepo.EXPECT().PatchPost(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error patch"))
+
+		err := suite.Cs.PatchPost(suite.Ctx, dto.PatchPostRequest{Title: &title}, 1, 0)
+		suite.Error(err)
+		suite.Equal(err.Error(), "error patch")
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_UpdatePostStatus() {
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Cs.UpdatePostStatus(suite.Ctx, "published", 1, 0)
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find post with id 1")
+	})
+
+	suite.Run("error transition not allowed", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft, Version: 1}, nil)
+
+		err := suite.Cs.UpdatePostStatus(suite.Ctx, "archived", 1, 1)
+		suite.Equal(dto.ErrorConflict{Message: "cannot move post from draft to archived"}, err)
+	})
+
+	suite.Run("success publish sets published at", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusDraft, Version: 1}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePostStatus(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req model.Post) error {
+				suite.Equal(model.PostStatusPublished, req.Status)
+				suite.Equal(1, req.Version)
+				suite.NotNil(req.PublishedAt)
+				return nil
+			})
+
+		err := suite.Cs.UpdatePostStatus(suite.Ctx, "published", 1, 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("success unarchive keeps published at", func() {
+		publishedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusArchived, PublishedAt: &publishedAt, Version: 4}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePostStatus(gomock.Any(), model.Post{
+			ID:          1,
+			Status:      model.PostStatusPublished,
+			PublishedAt: &publishedAt,
+			Version:     4,
+		}).Return(nil)
+
+		err := suite.Cs.UpdatePostStatus(suite.Ctx, "published", 1, 0)
+		suite.NoError(err)
+	})
+
+	suite.Run("error version conflict", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished, Version: 2}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePostStatus(gomock.Any(), gomock.Any()).Return(model.ErrVersionConflict)
+
+		err := suite.Cs.UpdatePostStatus(suite.Ctx, "archived", 1, 2)
+		suite.Equal(dto.ErrorPreconditionFailed{Message: "post has been modified"}, err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_Authorization() {
+	authorID := 7
+	authored := &model.Post{ID: 1, Status: model.PostStatusPublished, Version: 3, UserID: &authorID}
+	as := func(userID int, role auth.Role) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: role})
+	}
+
+	suite.Run("create sets the author", func() {
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, post model.Post) error {
+			suite.Equal(&authorID, post.UserID)
+			return nil
+		})
+
+		err := suite.Cs.CreatePost(as(authorID, auth.RoleAuthor), suite.MockCreatePostReq)
+		suite.NoError(err)
+	})
+
+	suite.Run("error create by a viewer", func() {
+		err := suite.Cs.CreatePost(as(authorID, auth.RoleViewer), suite.MockCreatePostReq)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:create permission is required"})
+	})
+
+	suite.Run("error create anonymously", func() {
+		err := suite.Cs.CreatePost(context.Background(), suite.MockCreatePostReq)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:create permission is required"})
+	})
+
+	suite.Run("error update by another author", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(authored, nil)
+
+		err := suite.Cs.UpdatePost(as(8, auth.RoleAuthor), suite.MockCreatePostReq, 1, 3)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:edit_any permission is required"})
+	})
+
+	suite.Run("update by an editor", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(authored, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), gomock.Any()).Return(nil)
+
+		err := suite.Cs.UpdatePost(as(8, auth.RoleEditor), suite.MockCreatePostReq, 1, 3)
+		suite.NoError(err)
+	})
+
+	suite.Run("error delete by an editor before checking the version", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(authored, nil)
+
+		err := suite.Cs.DeletePost(as(8, auth.RoleEditor), 1, 2)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:delete_any permission is required"})
+	})
+
+	suite.Run("delete by the author", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(authored, nil)
+		suite.MockPostRepo.EXPECT().DeletePost(gomock.Any(), *authored).Return(nil)
+
+		err := suite.Cs.DeletePost(as(authorID, auth.RoleAuthor), 1, 3)
+		suite.NoError(err)
+	})
+
+	suite.Run("error restore by a viewer", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(authored, nil)
+
+		err := suite.Cs.RestorePost(as(authorID, auth.RoleViewer), 1)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:delete permission is required"})
+	})
+
+	suite.Run("error purge by the author", func() {
+		err := suite.Cs.PurgePost(as(authorID, auth.RoleAuthor), 1)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the trash:purge permission is required"})
+	})
+
+	suite.Run("get post with its author", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{
+			ID:     1,
+			Status: model.PostStatusPublished,
+			UserID: &authorID,
+			User:   &model.User{ID: authorID, Name: "jane", Email: "jane@example.com"},
+		}, nil)
+
+		res, err := suite.Cs.GetPost(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(&dto.Author{ID: authorID, Name: "jane"}, res.Author)
+	})
+
+	suite.Run("get posts by author", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:    11,
+			Status:   model.PostStatusPublished,
+			AuthorID: authorID,
+		}).Return([]model.Post{}, int64(0), nil)
+
+		res, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Author: authorID})
+		suite.NoError(err)
+		suite.Empty(res)
+	})
+
+	draft := &model.Post{ID: 2, Status: model.PostStatusDraft, UserID: &authorID}
+
+	suite.Run("error get draft anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 2).Return(draft, nil)
+
+		_, err := suite.Cs.GetPost(context.Background(), 2)
+		suite.Equal(dto.ErrorNotFound{EntityName: "post", EntityID: 2}, err)
+	})
+
+	suite.Run("error get draft of another author", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 2).Return(draft, nil)
+
+		_, err := suite.Cs.GetPost(as(8, auth.RoleAuthor), 2)
+		suite.Equal(dto.ErrorNotFound{EntityName: "post", EntityID: 2}, err)
+	})
+
+	suite.Run("error get draft by slug anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPostBySlug(gomock.Any(), "draft").Return(draft, nil)
+
+		_, err := suite.Cs.GetPostBySlug(context.Background(), "draft")
+		suite.Equal(dto.ErrorNotFound{EntityName: "post", EntitySlug: "draft"}, err)
+	})
+
+	suite.Run("error get revisions of a draft anonymously", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 2).Return(draft, nil)
+
+		_, _, err := suite.Cs.GetPostRevisions(context.Background(), 2, dto.GetPostRevisionsRequest{})
+		suite.Equal(dto.ErrorNotFound{EntityName: "post", EntityID: 2}, err)
+	})
+
+	suite.Run("get draft by its author", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 2).Return(draft, nil)
+
+		res, err := suite.Cs.GetPost(as(authorID, auth.RoleViewer), 2)
+		suite.NoError(err)
+		suite.Equal("draft", res.Status)
+	})
+
+	suite.Run("get draft by an editor", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 2).Return(draft, nil)
+
+		_, err := suite.Cs.GetPost(as(8, auth.RoleEditor), 2)
+		suite.NoError(err)
+	})
+
+	suite.Run("error list drafts anonymously", func() {
+		_, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Status: "draft"})
+		suite.Equal(dto.ErrorUnauthorized{Message: "authentication required to list unpublished posts"}, err)
+	})
+
+	suite.Run("list drafts of the author", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:    11,
+			Status:   model.PostStatusDraft,
+			AuthorID: authorID,
+		}).Return([]model.Post{}, int64(0), nil)
+
+		_, _, err := suite.Cs.GetPosts(as(authorID, auth.RoleAuthor), dto.GetPostsRequest{Status: "draft"})
+		suite.NoError(err)
+	})
+
+	suite.Run("error list archived posts of another author", func() {
+		_, _, err := suite.Cs.GetPosts(as(8, auth.RoleAuthor), dto.GetPostsRequest{Status: "archived", Author: authorID})
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:edit_any permission is required"})
+	})
+
+	suite.Run("list drafts of every author by an editor", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:  11,
+			Status: model.PostStatusDraft,
+		}).Return([]model.Post{}, int64(0), nil)
+
+		_, _, err := suite.Cs.GetPosts(as(8, auth.RoleEditor), dto.GetPostsRequest{Status: "draft"})
 		suite.NoError(err)
 	})
 }
@@ -202,26 +837,44 @@ func (suite *TestPostServiceSuite) TestPostService_GetPost() {
 				ID:    1,
 				Label: "test tag",
 			}},
+			Status: model.PostStatusPublished,
 		}, nil)
 
 		res, err := suite.Cs.GetPost(context.Background(), 1)
 		suite.NoError(err)
 		suite.NotNil(res)
 	})
+
+	suite.Run("success with reactions", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), gomock.Any()).Return(&model.Post{
+			ID:     1,
+			Status: model.PostStatusPublished,
+			ReactionCounts: []model.PostReactionCount{
+				{PostID: 1, Kind: model.ReactionLike, Count: 3},
+				{PostID: 1, Kind: model.ReactionLaugh, Count: 0},
+				{PostID: 1, Kind: model.ReactionLove, Count: 1},
+			},
+		}, nil)
+
+		res, err := suite.Cs.GetPost(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(map[string]int{"like": 3, "love": 1}, res.Reactions)
+	})
 }
 
 func (suite *TestPostServiceSuite) TestPostService_GetPosts() {
 	suite.Run("error when get posts", func() {
-		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any()).Return(nil, errors.New("err from db"))
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("err from db"))
 
-		res, err := suite.Cs.GetPosts(context.Background())
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{})
 		suite.Error(err)
 		suite.Nil(res)
+		suite.Nil(pagination)
 		suite.Equal(err.Error(), "err from db")
 	})
 
-	suite.Run("success", func() {
-		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any()).Return([]model.Post{{
+	suite.Run("success with default limit", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{Limit: 11, Status: model.PostStatusPublished}).Return([]model.Post{{
 			ID:      1,
 			Title:   "test",
 			Content: "test",
@@ -229,10 +882,701 @@ func (suite *TestPostServiceSuite) TestPostService_GetPosts() {
 				ID:    1,
 				Label: "test tag",
 			}}},
-		}, nil)
+		}, int64(1), nil)
 
-		res, err := suite.Cs.GetPosts(context.Background())
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{})
 		suite.NoError(err)
 		suite.NotNil(res)
+		suite.Equal(&dto.Pagination{Page: 1, PerPage: 10, Total: 1}, pagination)
+	})
+
+	suite.Run("success with limit and offset", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{Limit: 6, Offset: 10, Status: model.PostStatusPublished}).Return([]model.Post{}, int64(30), nil)
+
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Limit: 5, Offset: 10})
+		suite.NoError(err)
+		suite.Empty(res)
+		suite.Equal(&dto.Pagination{Page: 3, PerPage: 5, Total: 30}, pagination)
+	})
+
+	suite.Run("success with fields", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:  11,
+			Status: model.PostStatusPublished,
+			Fields: &model.PostFields{Columns: []string{"id", "title", "excerpt"}, Tags: true},
+		}).Return([]model.Post{{
+			ID:      1,
+			Title:   "test",
+			Excerpt: "test",
+			Tags:    []*model.Tag{{ID: 1, Label: "go"}},
+		}}, int64(1), nil)
+
+		res, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Fields: "title, excerpt,tags,title"})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal([]string{"id", "title", "excerpt", "tags"}, res[0].Fields)
+		suite.Equal([]string{"go"}, res[0].Tags)
+	})
+
+	suite.Run("success with fields and author", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:  11,
+			Status: model.PostStatusPublished,
+			Fields: &model.PostFields{Columns: []string{"id", "title", "user_id"}, Author: true},
+		}).Return([]model.Post{}, int64(0), nil)
+
+		res, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Fields: "title", Include: "author"})
+		suite.NoError(err)
+		suite.Empty(res)
+	})
+
+	suite.Run("success including comments", func() {
+		parentID := 2
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:  11,
+			Status: model.PostStatusPublished,
+			Fields: &model.PostFields{Tags: true, Author: true, Reactions: true, Comments: true, CommentsLimit: 20},
+		}).Return([]model.Post{{
+			ID:    1,
+			Title: "test",
+			User:  &model.User{ID: 7, Name: "jane"},
+			Comments: []model.Comment{
+				{ID: 2, Body: "first"},
+				{ID: 3, Body: "reply", ParentID: &parentID},
+				{ID: 4, Body: "second"},
+			},
+		}}, int64(1), nil)
+
+		res, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Include: "comments"})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Nil(res[0].Fields)
+		suite.Equal(&dto.Author{ID: 7, Name: "jane"}, res[0].Author)
+		suite.Equal([]dto.GetCommentResponse{
+			{ID: 2, Body: "first", Replies: []dto.GetCommentResponse{
+				{ID: 3, ParentID: &parentID, Body: "reply", Replies: []dto.GetCommentResponse{}},
+			}},
+			{ID: 4, Body: "second", Replies: []dto.GetCommentResponse{}},
+		}, res[0].Comments)
+	})
+
+	suite.Run("error unknown field", func() {
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Fields: "id,password"})
+		suite.Equal(dto.ErrorBadRequest{Message: "unknown field password"}, err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("error unknown include", func() {
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Include: "tags"})
+		suite.Equal(dto.ErrorBadRequest{Message: "unknown include tags"}, err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("error invalid cursor", func() {
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Cursor: "eyJpZCI6MX0.Zm9yZ2Vk"})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+		suite.Equal(err, dto.ErrorBadRequest{Message: "invalid cursor"})
+	})
+
+	suite.Run("success with cursor", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{Limit: 3, Status: model.PostStatusPublished}).Return([]model.Post{
+			{ID: 9}, {ID: 8}, {ID: 7},
+		}, int64(9), nil)
+
+		res, pagination, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Limit: 2})
+		suite.NoError(err)
+		suite.Len(res, 2)
+		suite.NotEmpty(pagination.NextCursor)
+
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{Limit: 3, BeforeID: 8, Status: model.PostStatusPublished}).Return([]model.Post{
+			{ID: 7}, {ID: 6},
+		}, int64(9), nil)
+
+		res, pagination, err = suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Limit: 2, Offset: 4, Cursor: pagination.NextCursor})
+		suite.NoError(err)
+		suite.Len(res, 2)
+		suite.Equal(&dto.Pagination{PerPage: 2, Total: 9}, pagination)
+	})
+
+	suite.Run("cursor signed by other secret", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), gomock.Any()).Return([]model.Post{
+			{ID: 9}, {ID: 8},
+		}, int64(9), nil)
+
+		_, pagination, err := NewPostService(suite.MockPostRepo, WithCursorSecret([]byte("other"))).
+			GetPosts(context.Background(), dto.GetPostsRequest{Limit: 1})
+		suite.NoError(err)
+
+		_, _, err = suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Cursor: pagination.NextCursor})
+		suite.Equal(err, dto.ErrorBadRequest{Message: "invalid cursor"})
+	})
+
+	suite.Run("success with tags", func() {
+		suite.MockPostRepo.EXPECT().GetPosts(gomock.Any(), model.PostFilter{
+			Limit:        11,
+			Tags:         []string{"go", "sql"},
+			MatchAllTags: true,
+			Status:       model.PostStatusPublished,
+		}).Return([]model.Post{}, int64(0), nil)
+
+		res, _, err := suite.Cs.GetPosts(context.Background(), dto.GetPostsRequest{Tags: []string{"Go", "sql", " go"}, Match: "all"})
+		suite.NoError(err)
+		suite.Empty(res)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_GetPostsSummary() {
+	suite.Run("error from repository", func() {
+		suite.MockPostRepo.EXPECT().GetPostsSummary(gomock.Any(), gomock.Any()).Return(nil, errors.New("err from db"))
+
+		_, err := suite.Cs.GetPostsSummary(context.Background(), dto.GetPostsRequest{})
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockPostRepo.EXPECT().GetPostsSummary(gomock.Any(), model.PostFilter{
+			Tags:         []string{"go"},
+			MatchAllTags: true,
+			Status:       model.PostStatusDraft,
+		}).Return(&model.PostsSummary{Total: 2, LastUpdatedAt: updatedAt}, nil)
+
+		res, err := suite.Cs.GetPostsSummary(suite.Ctx, dto.GetPostsRequest{
+			Limit:  5,
+			Offset: 10,
+			Tags:   []string{" Go"},
+			Match:  "all",
+			Status: "draft",
+		})
+		suite.NoError(err)
+		suite.Equal(&dto.PostsSummary{Total: 2, LastModified: updatedAt}, res)
+	})
+
+	suite.Run("error drafts anonymously", func() {
+		_, err := suite.Cs.GetPostsSummary(context.Background(), dto.GetPostsRequest{Status: "draft"})
+		suite.Equal(dto.ErrorUnauthorized{Message: "authentication required to list unpublished posts"}, err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_GetTrashedPosts() {
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 1, Role: auth.RoleAdmin})
+
+	suite.Run("error anonymous", func() {
+		res, pagination, err := suite.Cs.GetTrashedPosts(context.Background(), dto.GetTrashedPostsRequest{})
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:delete permission is required"})
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("error by a viewer", func() {
+		viewer := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 3, Role: auth.RoleViewer})
+
+		res, pagination, err := suite.Cs.GetTrashedPosts(viewer, dto.GetTrashedPostsRequest{})
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:delete permission is required"})
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("error when get trashed posts", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPosts(gomock.Any(), 0, 10, 0).Return(nil, int64(0), errors.New("err from db"))
+
+		res, pagination, err := suite.Cs.GetTrashedPosts(admin, dto.GetTrashedPostsRequest{})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("success own posts of an editor", func() {
+		editor := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 2, Role: auth.RoleEditor})
+		suite.MockPostRepo.EXPECT().GetTrashedPosts(gomock.Any(), 2, 10, 0).Return([]model.Post{}, int64(0), nil)
+
+		res, pagination, err := suite.Cs.GetTrashedPosts(editor, dto.GetTrashedPostsRequest{})
+		suite.NoError(err)
+		suite.Empty(res)
+		suite.Equal(&dto.Pagination{Page: 1, PerPage: 10, Total: 0}, pagination)
+	})
+
+	suite.Run("success", func() {
+		deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockPostRepo.EXPECT().GetTrashedPosts(gomock.Any(), 0, 2, 2).Return([]model.Post{{
+			ID:        1,
+			Title:     "test",
+			Content:   "test",
+			DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true},
+		}}, int64(3), nil)
+
+		res, pagination, err := suite.Cs.GetTrashedPosts(admin, dto.GetTrashedPostsRequest{Limit: 2, Offset: 2})
+		suite.NoError(err)
+		suite.Len(res, 1)
+		suite.Equal(&deletedAt, res[0].DeletedAt)
+		suite.Equal(&dto.Pagination{Page: 2, PerPage: 2, Total: 3}, pagination)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_RestorePost() {
+	suite.Run("error not found", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Cs.RestorePost(suite.Ctx, 1)
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find deleted post with id 1")
+	})
+
+	suite.Run("error when restore post", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(&model.Post{ID: 1}, nil)
+		suite.MockPostRepo.EXPECT().RestorePost(gomock.Any(), 1).Return(errors.New("err from db"))
+
+		err := suite.Cs.RestorePost(suite.Ctx, 1)
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(&model.Post{ID: 1}, nil)
+		suite.MockPostRepo.EXPECT().RestorePost(gomock.Any(), 1).Return(nil)
+
+		err := suite.Cs.RestorePost(suite.Ctx, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_PurgePost() {
+	suite.Run("error not found", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Cs.PurgePost(suite.Ctx, 1)
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find deleted post with id 1")
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetTrashedPost(gomock.Any(), 1).Return(&model.Post{ID: 1}, nil)
+		suite.MockPostRepo.EXPECT().PurgePost(gomock.Any(), 1).Return(nil)
+
+		err := suite.Cs.PurgePost(suite.Ctx, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_PurgeTrash() {
+	suite.Run("purges posts deleted before the retention", func() {
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		suite.MockPostRepo.EXPECT().PurgeTrashedPosts(gomock.Any(), now.Add(-2*time.Hour)).Return(int64(1), nil)
+
+		ps := NewPostService(suite.MockPostRepo,
+			WithTrashRetention(2*time.Hour),
+			WithClock(func() time.Time { return now }))
+		err := ps.PurgeTrash(context.Background())
+		suite.NoError(err)
+	})
+
+	suite.Run("error from repository", func() {
+		suite.MockPostRepo.EXPECT().PurgeTrashedPosts(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("err from db"))
+
+		err := suite.Cs.PurgeTrash(context.Background())
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_SchedulePost() {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ps := NewPostService(suite.MockPostRepo, WithClock(func() time.Time { return now }))
+
+	suite.Run("error create scheduled in the past", func() {
+		publishAt := now
+		err := ps.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:     "test",
+			Content:   "test",
+			Tags:      []string{"go"},
+			PublishAt: &publishAt,
+		})
+		suite.Equal(dto.ErrorBadRequest{Message: "publish_at must be in the future"}, err)
+	})
+
+	suite.Run("success create scheduled", func() {
+		publishAt := now.Add(time.Hour)
+		userID := 1
+		suite.MockPostRepo.EXPECT().CreatePost(gomock.Any(), model.Post{
+			Title:         "test",
+			Slug:          "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "go", Slug: "go"}},
+			UserID:        &userID,
+			Status:        model.PostStatusDraft,
+			PublishAt:     &publishAt,
+		}).Return(nil)
+
+		err := ps.CreatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:     "test",
+			Content:   "test",
+			Tags:      []string{"go"},
+			PublishAt: &publishAt,
+		})
+		suite.NoError(err)
+	})
+
+	suite.Run("error update schedules a published post", func() {
+		publishAt := now.Add(time.Hour)
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished, Version: 1}, nil)
+
+		err := ps.UpdatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:     "test",
+			Content:   "test",
+			Tags:      []string{"go"},
+			PublishAt: &publishAt,
+		}, 1, 1)
+		suite.Equal(dto.ErrorConflict{Message: "only drafts can be scheduled"}, err)
+	})
+
+	suite.Run("success update reschedules a draft", func() {
+		publishAt := now.Add(time.Hour)
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{
+			ID:      1,
+			Status:  model.PostStatusDraft,
+			Tags:    []*model.Tag{{ID: 1, Label: "go"}},
+			Version: 1,
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "test",
+			Slug:          "test",
+			Content:       "test",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>test</p>\n",
+			Excerpt:       "test",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "go", Slug: "go"}},
+			PublishAt:     &publishAt,
+			Version:       1,
+		}).Return(nil)
+
+		err := ps.UpdatePost(suite.Ctx, dto.CreateOrUpdatePostRequest{
+			Title:     "test",
+			Content:   "test",
+			Tags:      []string{"go"},
+			PublishAt: &publishAt,
+		}, 1, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_PublishScheduledPosts() {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ps := NewPostService(suite.MockPostRepo, WithClock(func() time.Time { return now }))
+
+	suite.Run("publishes batches until none is full", func() {
+		gomock.InOrder(
+			suite.MockPostRepo.EXPECT().PublishDuePosts(gomock.Any(), now, 100).Return(int64(100), nil),
+			suite.MockPostRepo.EXPECT().PublishDuePosts(gomock.Any(), now, 100).Return(int64(3), nil),
+		)
+
+		err := ps.PublishScheduledPosts(context.Background())
+		suite.NoError(err)
+	})
+
+	suite.Run("error from repository", func() {
+		suite.MockPostRepo.EXPECT().PublishDuePosts(gomock.Any(), now, 100).Return(int64(0), errors.New("err from db"))
+
+		err := ps.PublishScheduledPosts(context.Background())
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_RenderLegacyPosts() {
+	suite.Run("renders batches until none is full", func() {
+		batch := make([]model.Post, 100)
+		for i := range batch {
+			batch[i] = model.Post{ID: i + 1, Content: "one"}
+		}
+		gomock.InOrder(
+			suite.MockPostRepo.EXPECT().GetUnrenderedPosts(gomock.Any(), 0, 100).Return(batch, nil),
+			suite.MockPostRepo.EXPECT().UpdateRenderedContent(gomock.Any(), gomock.Any()).Return(nil).Times(100),
+			suite.MockPostRepo.EXPECT().GetUnrenderedPosts(gomock.Any(), 100, 100).Return([]model.Post{{
+				ID:            101,
+				Content:       "one *two*",
+				ContentFormat: model.ContentFormatMarkdown,
+			}}, nil),
+			suite.MockPostRepo.EXPECT().UpdateRenderedContent(gomock.Any(), model.Post{
+				ID:            101,
+				Content:       "one *two*",
+				ContentFormat: model.ContentFormatMarkdown,
+				ContentHTML:   "<p>one <em>two</em></p>\n",
+				Excerpt:       "one two",
+				WordCount:     2,
+				ReadingTime:   1,
+			}).Return(nil),
+		)
+
+		rendered, err := suite.Cs.RenderLegacyPosts(context.Background())
+		suite.NoError(err)
+		suite.Equal(101, rendered)
+	})
+
+	suite.Run("error when get unrendered posts", func() {
+		suite.MockPostRepo.EXPECT().GetUnrenderedPosts(gomock.Any(), 0, 100).Return(nil, errors.New("err from db"))
+
+		rendered, err := suite.Cs.RenderLegacyPosts(context.Background())
+		suite.Error(err)
+		suite.Zero(rendered)
+	})
+
+	suite.Run("error when update rendered content", func() {
+		suite.MockPostRepo.EXPECT().GetUnrenderedPosts(gomock.Any(), 0, 100).Return([]model.Post{{ID: 1, Content: "one"}}, nil)
+		suite.MockPostRepo.EXPECT().UpdateRenderedContent(gomock.Any(), gomock.Any()).Return(errors.New("err from db"))
+
+		rendered, err := suite.Cs.RenderLegacyPosts(context.Background())
+		suite.Error(err)
+		suite.Zero(rendered)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_GetPostRevisions() {
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, _, err := suite.Cs.GetPostRevisions(context.Background(), 1, dto.GetPostRevisionsRequest{})
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find post with id 1")
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished, Version: 3}, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevisions(gomock.Any(), 1, 10, 0).Return([]model.PostRevision{
+			{PostID: 1, Revision: 2, Title: "b", Content: "b", Tags: []string{"x"}},
+			{PostID: 1, Revision: 1, Title: "a", Content: "a", Tags: []string{"x"}},
+		}, int64(2), nil)
+
+		res, pagination, err := suite.Cs.GetPostRevisions(context.Background(), 1, dto.GetPostRevisionsRequest{})
+		suite.NoError(err)
+		suite.Len(res, 2)
+		suite.Equal(2, res[0].Revision)
+		suite.Equal(&dto.Pagination{Page: 1, PerPage: 10, Total: 2}, pagination)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_GetPostRevision() {
+	post := &model.Post{
+		ID:      1,
+		Title:   "current",
+		Content: "current",
+		Tags:    []*model.Tag{{ID: 1, Label: "x"}},
+		Status:  model.PostStatusPublished,
+		Version: 3,
+	}
+
+	suite.Run("success current version", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		res, err := suite.Cs.GetPostRevision(context.Background(), 1, 3)
+		suite.NoError(err)
+		suite.Equal("current", res.Title)
+		suite.Equal([]string{"x"}, res.Tags)
+	})
+
+	suite.Run("success stored revision", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 2).Return(&model.PostRevision{PostID: 1, Revision: 2, Title: "old"}, nil)
+
+		res, err := suite.Cs.GetPostRevision(context.Background(), 1, 2)
+		suite.NoError(err)
+		suite.Equal("old", res.Title)
+	})
+
+	suite.Run("error revision not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 5).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.Cs.GetPostRevision(context.Background(), 1, 5)
+		suite.Error(err)
+		suite.Equal(err.Error(), "cannot find revision with id 5")
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_DiffPostRevisions() {
+	post := &model.Post{
+		ID:      1,
+		Title:   "current",
+		Content: "line 1\nline 2 changed",
+		Tags:    []*model.Tag{{ID: 1, Label: "x"}, {ID: 2, Label: "y"}},
+		Status:  model.PostStatusPublished,
+		Version: 3,
+	}
+
+	suite.Run("success against current version", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 1).Return(&model.PostRevision{
+			PostID:   1,
+			Revision: 1,
+			Title:    "old",
+			Content:  "line 1\nline 2",
+			Tags:     []string{"x"},
+		}, nil)
+
+		res, err := suite.Cs.DiffPostRevisions(context.Background(), 1, dto.DiffPostRevisionsRequest{From: 1})
+		suite.NoError(err)
+		suite.Equal(1, res.From)
+		suite.Equal(3, res.To)
+		suite.Equal(`--- revision 1
++++ revision 3
+@@ -1,5 +1,5 @@
+-title: old
+-tags: x
++title: current
++tags: x, y
+ 
+ line 1
+-line 2
++line 2 changed
+`, res.Diff)
+	})
+
+	suite.Run("success same revision", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		res, err := suite.Cs.DiffPostRevisions(context.Background(), 1, dto.DiffPostRevisionsRequest{From: 3, To: 3})
+		suite.NoError(err)
+		suite.Empty(res.Diff)
+	})
+
+	suite.Run("error revision not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 7).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := suite.Cs.DiffPostRevisions(context.Background(), 1, dto.DiffPostRevisionsRequest{From: 7})
+		suite.Error(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_RestorePostRevision() {
+	post := &model.Post{
+		ID:      1,
+		Title:   "current",
+		Slug:    "current",
+		Content: "current",
+		Tags:    []*model.Tag{{ID: 1, Label: "x"}, {ID: 2, Label: "y"}},
+		Version: 3,
+	}
+
+	suite.Run("error post modified", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+
+		err := suite.Cs.RestorePostRevision(suite.Ctx, 1, 1, 2)
+		suite.Equal(dto.ErrorPreconditionFailed{Message: "post has been modified"}, err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(post, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 1).Return(&model.PostRevision{
+			PostID:   1,
+			Revision: 1,
+			Title:    "old",
+			Content:  "old",
+			Tags:     []string{"x"},
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "old",
+			Content:       "old",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>old</p>\n",
+			Excerpt:       "old",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "x", Slug: "x"}},
+			Version:       3,
+		}, 2).Return(nil)
+
+		err := suite.Cs.RestorePostRevision(suite.Ctx, 1, 1, 3)
+		suite.NoError(err)
+	})
+
+	suite.Run("success keeping a due schedule", func() {
+		publishAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		scheduled := *post
+		scheduled.Status = model.PostStatusDraft
+		scheduled.PublishAt = &publishAt
+		ps := NewPostService(suite.MockPostRepo, WithClock(func() time.Time { return publishAt.Add(time.Minute) }))
+
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&scheduled, nil)
+		suite.MockPostRepo.EXPECT().GetPostRevision(gomock.Any(), 1, 1).Return(&model.PostRevision{
+			PostID:   1,
+			Revision: 1,
+			Title:    "old",
+			Content:  "old",
+			Tags:     []string{"x"},
+		}, nil)
+		suite.MockPostRepo.EXPECT().UpdatePost(gomock.Any(), model.Post{
+			ID:            1,
+			Title:         "old",
+			Content:       "old",
+			ContentFormat: model.ContentFormatPlain,
+			ContentHTML:   "<p>old</p>\n",
+			Excerpt:       "old",
+			WordCount:     1,
+			ReadingTime:   1,
+			Tags:          []*model.Tag{{Label: "x", Slug: "x"}},
+			PublishAt:     &publishAt,
+			Version:       3,
+		}, 2).Return(nil)
+
+		err := ps.RestorePostRevision(suite.Ctx, 1, 1, 3)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestPostServiceSuite) TestPostService_SearchPosts() {
+	suite.Run("error when search posts", func() {
+		suite.MockPostRepo.EXPECT().SearchPosts(gomock.Any(), "golang", 10, 0).Return(nil, int64(0), errors.New("err from db"))
+
+		res, pagination, err := suite.Cs.SearchPosts(context.Background(), dto.SearchPostsRequest{Query: "golang"})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+		suite.Equal(err.Error(), "err from db")
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().SearchPosts(gomock.Any(), "golang", 5, 5).Return([]model.PostSearchResult{{
+			Post: model.Post{
+				ID:          1,
+				Title:       "test",
+				Content:     "golang",
+				ContentHTML: "<p>golang</p>\n",
+				Excerpt:     "golang",
+				WordCount:   1,
+				ReadingTime: 1,
+				Tags:        []*model.Tag{{ID: 1, Label: "go"}},
+			},
+			Rank:    0.5,
+			Snippet: "\x02golang\x03 <img src=x onerror=alert(1)>",
+		}}, int64(6), nil)
+
+		res, pagination, err := suite.Cs.SearchPosts(context.Background(), dto.SearchPostsRequest{Query: "golang", Limit: 5, Offset: 5})
+		suite.NoError(err)
+		suite.Equal([]dto.SearchPostResponse{{
+			GetPostResponse: dto.GetPostResponse{
+				ID:            1,
+				Title:         "test",
+				Content:       "golang",
+				ContentFormat: "plain",
+				ContentHTML:   "<p>golang</p>\n",
+				Excerpt:       "golang",
+				WordCount:     1,
+				ReadingTime:   1,
+				Tags:          []string{"go"},
+			},
+			Rank:    0.5,
+			Snippet: "<mark>golang</mark> &lt;img src=x onerror=alert(1)&gt;",
+		}}, res)
+		suite.Equal(&dto.Pagination{Page: 2, PerPage: 5, Total: 6}, pagination)
 	})
 }
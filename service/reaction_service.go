@@ -0,0 +1,112 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+var errReactionNotPublished = dto.ErrorConflict{Message: "only published posts can be reacted to"}
+
+type (
+	IReactionRepository interface {
+		AddReaction(ctx context.Context, req model.Reaction) error
+		RemoveReaction(ctx context.Context, req model.Reaction) error
+	}
+
+	// IReactionPostRepository gets the posts the reactions are added to.
+	IReactionPostRepository interface {
+		GetPost(ctx context.Context, id int) (*model.Post, error)
+	}
+
+	ReactionService struct {
+		reactionRepository IReactionRepository
+		postRepository     IReactionPostRepository
+	}
+)
+
+func NewReactionService(reactionRepository IReactionRepository, postRepository IReactionPostRepository) *ReactionService {
+	return &ReactionService{
+		reactionRepository: reactionRepository,
+		postRepository:     postRepository,
+	}
+}
+
+// AddReaction reacts to the post on behalf of the user of ctx, reacting again
+// with the same kind changes nothing. Only published posts can be reacted to.
+func (rs *ReactionService) AddReaction(ctx context.Context, postID int, kind string) error {
+	err := authorize(ctx, auth.PermReactToPost)
+	if err != nil {
+		return err
+	}
+
+	post, err := rs.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if post.Status != model.PostStatusPublished {
+		return errReactionNotPublished
+	}
+
+	err = rs.reactionRepository.AddReaction(ctx, model.Reaction{
+		PostID: postID,
+		UserID: auth.UserID(ctx),
+		Kind:   model.ReactionKind(kind),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveReaction removes the reaction of the user of ctx to the post, if any.
+func (rs *ReactionService) RemoveReaction(ctx context.Context, postID int, kind string) error {
+	err := authorize(ctx, auth.PermReactToPost)
+	if err != nil {
+		return err
+	}
+
+	_, err = rs.getPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	err = rs.reactionRepository.RemoveReaction(ctx, model.Reaction{
+		PostID: postID,
+		UserID: auth.UserID(ctx),
+		Kind:   model.ReactionKind(kind),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getPost gets the post, failing with a not found error when it does not
+// exist or the user of ctx cannot see it.
+func (rs *ReactionService) getPost(ctx context.Context, id int) (*model.Post, error) {
+	post, err := rs.postRepository.GetPost(ctx, id)
+	if err == nil && !canSeePost(ctx, post) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "post",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return post, nil
+}
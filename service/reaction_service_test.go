@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestReactionServiceSuite struct {
+	suite.Suite
+
+	MockReactionRepo *gomockService.MockIReactionRepository
+	MockPostRepo     *gomockService.MockIReactionPostRepository
+	Rs               *ReactionService
+	Ctrl             *gomock.Controller
+}
+
+func (suite *TestReactionServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockReactionRepo = gomockService.NewMockIReactionRepository(suite.Ctrl)
+	suite.MockPostRepo = gomockService.NewMockIReactionPostRepository(suite.Ctrl)
+	suite.Rs = NewReactionService(suite.MockReactionRepo, suite.MockPostRepo)
+}
+
+func (suite *TestReactionServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestReactionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestReactionServiceSuite))
+}
+
+func (suite *TestReactionServiceSuite) TestReactionService_AddReaction() {
+	viewer := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7, Role: auth.RoleViewer})
+
+	suite.Run("error anonymously", func() {
+		err := suite.Rs.AddReaction(context.Background(), 1, "like")
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the posts:react permission is required"})
+	})
+
+	suite.Run("error post not found", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Rs.AddReaction(viewer, 1, "like")
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error draft of another user", func() {
+		authorID := 2
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, UserID: &authorID, Status: model.PostStatusDraft}, nil)
+
+		err := suite.Rs.AddReaction(viewer, 1, "like")
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error own draft", func() {
+		authorID := 7
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, UserID: &authorID, Status: model.PostStatusDraft}, nil)
+
+		err := suite.Rs.AddReaction(viewer, 1, "like")
+		suite.ErrorIs(err, dto.ErrorConflict{Message: "only published posts can be reacted to"})
+	})
+
+	suite.Run("success", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished}, nil)
+		suite.MockReactionRepo.EXPECT().AddReaction(gomock.Any(), model.Reaction{PostID: 1, UserID: 7, Kind: model.ReactionLike}).Return(nil)
+
+		err := suite.Rs.AddReaction(viewer, 1, "like")
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestReactionServiceSuite) TestReactionService_RemoveReaction() {
+	viewer := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7, Role: auth.RoleViewer})
+
+	suite.Run("error archived post of another user", func() {
+		authorID := 2
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, UserID: &authorID, Status: model.PostStatusArchived}, nil)
+
+		err := suite.Rs.RemoveReaction(viewer, 1, "like")
+		suite.ErrorIs(err, dto.ErrorNotFound{EntityName: "post", EntityID: 1})
+	})
+
+	suite.Run("error when remove", func() {
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, Status: model.PostStatusPublished}, nil)
+		suite.MockReactionRepo.EXPECT().RemoveReaction(gomock.Any(), gomock.Any()).Return(errors.New("err from db"))
+
+		err := suite.Rs.RemoveReaction(viewer, 1, "like")
+		suite.Error(err)
+	})
+
+	suite.Run("success own archived post", func() {
+		authorID := 7
+		suite.MockPostRepo.EXPECT().GetPost(gomock.Any(), 1).Return(&model.Post{ID: 1, UserID: &authorID, Status: model.PostStatusArchived}, nil)
+		suite.MockReactionRepo.EXPECT().RemoveReaction(gomock.Any(), model.Reaction{PostID: 1, UserID: 7, Kind: model.ReactionLove}).Return(nil)
+
+		err := suite.Rs.RemoveReaction(viewer, 1, "love")
+		suite.NoError(err)
+	})
+}
@@ -0,0 +1,288 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"github.com/elangreza14/assetfindr-test/normalizer"
+	"gorm.io/gorm"
+)
+
+const defaultTagsLimit = 20
+
+type (
+	ITagRepository interface {
+		GetTags(ctx context.Context, limit, offset int) ([]model.TagSummary, int64, error)
+		GetTag(ctx context.Context, id int) (*model.Tag, error)
+		GetTagSummary(ctx context.Context, id int) (*model.TagSummary, error)
+		GetTagByLabel(ctx context.Context, label string) (*model.Tag, error)
+		GetAllTags(ctx context.Context) ([]model.Tag, error)
+		GetTagPosts(ctx context.Context, id, limit, offset int) ([]model.Post, int64, error)
+		UpdateTag(ctx context.Context, req model.Tag) error
+		MergeTag(ctx context.Context, sourceID, targetID int) error
+		DeleteTag(ctx context.Context, id int) error
+		DeleteOrphanTags(ctx context.Context) (int64, error)
+	}
+
+	TagService struct {
+		tagRepository ITagRepository
+		tagNormalizer normalizer.TagNormalizer
+	}
+
+	TagServiceOption func(*TagService)
+)
+
+// WithTagNormalizer sets how tag labels are normalized, they are lower cased
+// by default.
+func WithTagNormalizer(tagNormalizer normalizer.TagNormalizer) TagServiceOption {
+	return func(ts *TagService) {
+		ts.tagNormalizer = tagNormalizer
+	}
+}
+
+func NewTagService(tagRepository ITagRepository, opts ...TagServiceOption) *TagService {
+	ts := &TagService{
+		tagRepository: tagRepository,
+		tagNormalizer: normalizer.NewTagNormalizer(normalizer.CaseLower),
+	}
+
+	for _, opt := range opts {
+		opt(ts)
+	}
+
+	return ts
+}
+
+func (ts *TagService) GetTags(ctx context.Context, req dto.GetTagsRequest) ([]dto.GetTagResponse, *dto.Pagination, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultTagsLimit
+	}
+
+	tags, total, err := ts.tagRepository.GetTags(ctx, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetTagResponse, len(tags))
+	for i, tag := range tags {
+		res[i] = dto.GetTagResponse{
+			ID:        tag.ID,
+			Label:     tag.Label,
+			PostCount: tag.PostCount,
+		}
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+// GetTag gets the tag along with the number of published posts labeled with
+// it.
+func (ts *TagService) GetTag(ctx context.Context, id int) (*dto.GetTagResponse, error) {
+	tag, err := ts.tagRepository.GetTagSummary(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "tag",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return &dto.GetTagResponse{
+		ID:        tag.ID,
+		Label:     tag.Label,
+		PostCount: tag.PostCount,
+	}, nil
+}
+
+func (ts *TagService) GetTagPosts(ctx context.Context, id int, req dto.GetTagPostsRequest) ([]dto.GetPostResponse, *dto.Pagination, error) {
+	_, err := ts.getTag(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPostsLimit
+	}
+
+	posts, total, err := ts.tagRepository.GetTagPosts(ctx, id, limit, req.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := make([]dto.GetPostResponse, len(posts))
+	for i, post := range posts {
+		res[i] = newGetPostResponse(post)
+	}
+
+	return res, &dto.Pagination{
+		Page:    req.Offset/limit + 1,
+		PerPage: limit,
+		Total:   total,
+	}, nil
+}
+
+func (ts *TagService) UpdateTag(ctx context.Context, req dto.UpdateTagRequest, id int) error {
+	tag, err := ts.getTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	label := ts.tagNormalizer.Label(req.Label)
+	if label == "" {
+		return errBlankTagLabel
+	}
+
+	if tag.Label == label {
+		return nil
+	}
+
+	existingTag, err := ts.tagRepository.GetTagByLabel(ctx, label)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if existingTag != nil && existingTag.ID != id {
+		return dto.ErrorConflict{
+			Message: "tag " + label + " already exists, merge into it instead",
+		}
+	}
+
+	err = ts.tagRepository.UpdateTag(ctx, model.Tag{
+		ID:    id,
+		Label: label,
+		Slug:  normalizer.Slug(label),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ts *TagService) MergeTag(ctx context.Context, req dto.MergeTagRequest, id int) error {
+	if req.TargetID == id {
+		return dto.ErrorBadRequest{
+			Message: "cannot merge a tag into itself",
+		}
+	}
+
+	_, err := ts.getTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = ts.getTag(ctx, req.TargetID)
+	if err != nil {
+		return err
+	}
+
+	err = ts.tagRepository.MergeTag(ctx, id, req.TargetID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTag removes the tag from every post, as long as none of them is left
+// without tags.
+func (ts *TagService) DeleteTag(ctx context.Context, id int) error {
+	_, err := ts.getTag(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = ts.tagRepository.DeleteTag(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrLastTag) {
+			return dto.ErrorConflict{
+				Message: "tag is the last tag of some posts, tag them otherwise or merge it instead",
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// SweepOrphanTags removes every tag left without posts.
+func (ts *TagService) SweepOrphanTags(ctx context.Context) error {
+	_, err := ts.tagRepository.DeleteOrphanTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NormalizeTags normalizes the label of every stored tag, merging the tags
+// ending up with the same label into the oldest of them. It returns how many
+// tags were merged.
+func (ts *TagService) NormalizeTags(ctx context.Context) (int, error) {
+	tags, err := ts.tagRepository.GetAllTags(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// duplicates are merged before any rename, so that a renamed tag never
+	// collides with a duplicate that is still stored
+	merged := 0
+	canonicalTags := make(map[string]model.Tag)
+	toBeRenamed := make([]model.Tag, 0)
+	for _, tag := range tags {
+		label := ts.tagNormalizer.Label(tag.Label)
+		key := normalizer.Key(label)
+
+		if canonicalTag, ok := canonicalTags[key]; ok {
+			err = ts.tagRepository.MergeTag(ctx, tag.ID, canonicalTag.ID)
+			if err != nil {
+				return merged, err
+			}
+
+			merged++
+			continue
+		}
+
+		canonicalTags[key] = tag
+		slug := normalizer.Slug(label)
+		if tag.Label != label || tag.Slug != slug {
+			toBeRenamed = append(toBeRenamed, model.Tag{ID: tag.ID, Label: label, Slug: slug})
+		}
+	}
+
+	for _, tag := range toBeRenamed {
+		err = ts.tagRepository.UpdateTag(ctx, tag)
+		if err != nil {
+			return merged, err
+		}
+	}
+
+	return merged, nil
+}
+
+func (ts *TagService) getTag(ctx context.Context, id int) (*model.Tag, error) {
+	tag, err := ts.tagRepository.GetTag(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "tag",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
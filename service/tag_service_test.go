@@ -0,0 +1,289 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	"github.com/elangreza14/assetfindr-test/normalizer"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestTagServiceSuite struct {
+	suite.Suite
+
+	MockTagRepo *gomockService.MockITagRepository
+	Ts          *TagService
+	Ctrl        *gomock.Controller
+}
+
+func (suite *TestTagServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockTagRepo = gomockService.NewMockITagRepository(suite.Ctrl)
+	suite.Ts = NewTagService(suite.MockTagRepo)
+}
+
+func (suite *TestTagServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestTagServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestTagServiceSuite))
+}
+
+func (suite *TestTagServiceSuite) TestTagService_GetTags() {
+	suite.Run("error when get tags", func() {
+		suite.MockTagRepo.EXPECT().GetTags(gomock.Any(), 20, 0).Return(nil, int64(0), errors.New("err from db"))
+
+		res, pagination, err := suite.Ts.GetTags(context.Background(), dto.GetTagsRequest{})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTags(gomock.Any(), 2, 2).Return([]model.TagSummary{
+			{ID: 1, Label: "go", PostCount: 3},
+		}, int64(3), nil)
+
+		res, pagination, err := suite.Ts.GetTags(context.Background(), dto.GetTagsRequest{Limit: 2, Offset: 2})
+		suite.NoError(err)
+		suite.Equal([]dto.GetTagResponse{{ID: 1, Label: "go", PostCount: 3}}, res)
+		suite.Equal(&dto.Pagination{Page: 2, PerPage: 2, Total: 3}, pagination)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_GetTag() {
+	suite.Run("error tag not found", func() {
+		suite.MockTagRepo.EXPECT().GetTagSummary(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := suite.Ts.GetTag(context.Background(), 1)
+		suite.Equal(dto.ErrorNotFound{EntityName: "tag", EntityID: 1}, err)
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTagSummary(gomock.Any(), 1).Return(&model.TagSummary{ID: 1, Label: "go", PostCount: 3}, nil)
+
+		res, err := suite.Ts.GetTag(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(&dto.GetTagResponse{ID: 1, Label: "go", PostCount: 3}, res)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_GetTagPosts() {
+	suite.Run("error tag not found", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		res, pagination, err := suite.Ts.GetTagPosts(context.Background(), 1, dto.GetTagPostsRequest{})
+		suite.Equal(dto.ErrorNotFound{EntityName: "tag", EntityID: 1}, err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("error when get posts", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTagPosts(gomock.Any(), 1, 10, 0).Return(nil, int64(0), errors.New("err from db"))
+
+		res, pagination, err := suite.Ts.GetTagPosts(context.Background(), 1, dto.GetTagPostsRequest{})
+		suite.Error(err)
+		suite.Nil(res)
+		suite.Nil(pagination)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTagPosts(gomock.Any(), 1, 10, 0).Return([]model.Post{{
+			ID:          1,
+			Title:       "test",
+			Content:     "test",
+			ContentHTML: "<p>test</p>\n",
+			Excerpt:     "test",
+			WordCount:   1,
+			ReadingTime: 1,
+			Tags:        []*model.Tag{{ID: 1, Label: "go"}},
+		}}, int64(1), nil)
+
+		res, pagination, err := suite.Ts.GetTagPosts(context.Background(), 1, dto.GetTagPostsRequest{})
+		suite.NoError(err)
+		suite.Equal([]dto.GetPostResponse{{ID: 1, Title: "test", Content: "test", ContentFormat: "plain", ContentHTML: "<p>test</p>\n", Excerpt: "test", WordCount: 1, ReadingTime: 1, Tags: []string{"go"}}}, res)
+		suite.Equal(&dto.Pagination{Page: 1, PerPage: 10, Total: 1}, pagination)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_UpdateTag() {
+	suite.Run("error tag not found", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: "golang"}, 1)
+		suite.Equal(dto.ErrorNotFound{EntityName: "tag", EntityID: 1}, err)
+	})
+
+	suite.Run("error label already used", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTagByLabel(gomock.Any(), "golang").Return(&model.Tag{ID: 2, Label: "golang"}, nil)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: "golang"}, 1)
+		suite.Equal(dto.ErrorConflict{Message: "tag golang already exists, merge into it instead"}, err)
+	})
+
+	suite.Run("same label", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: "go"}, 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("error blank label", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: "   "}, 1)
+		suite.Equal(dto.ErrorBadRequest{Message: "tag label cannot be blank"}, err)
+	})
+
+	suite.Run("same label once normalized", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: " GO "}, 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTagByLabel(gomock.Any(), "go lang").Return(nil, gorm.ErrRecordNotFound)
+		suite.MockTagRepo.EXPECT().UpdateTag(gomock.Any(), model.Tag{ID: 1, Label: "go lang", Slug: "go-lang"}).Return(nil)
+
+		err := suite.Ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: " Go  Lang"}, 1)
+		suite.NoError(err)
+	})
+
+	suite.Run("success changing case only", func() {
+		ts := NewTagService(suite.MockTagRepo, WithTagNormalizer(normalizer.NewTagNormalizer(normalizer.CasePreserve)))
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTagByLabel(gomock.Any(), "Go").Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().UpdateTag(gomock.Any(), model.Tag{ID: 1, Label: "Go", Slug: "go"}).Return(nil)
+
+		err := ts.UpdateTag(context.Background(), dto.UpdateTagRequest{Label: "Go"}, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_MergeTag() {
+	suite.Run("error merge into itself", func() {
+		err := suite.Ts.MergeTag(context.Background(), dto.MergeTagRequest{TargetID: 1}, 1)
+		suite.Equal(dto.ErrorBadRequest{Message: "cannot merge a tag into itself"}, err)
+	})
+
+	suite.Run("error target not found", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 2).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Ts.MergeTag(context.Background(), dto.MergeTagRequest{TargetID: 2}, 1)
+		suite.Equal(dto.ErrorNotFound{EntityName: "tag", EntityID: 2}, err)
+	})
+
+	suite.Run("error when merge", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 2).Return(&model.Tag{ID: 2, Label: "golang"}, nil)
+		suite.MockTagRepo.EXPECT().MergeTag(gomock.Any(), 1, 2).Return(errors.New("err from db"))
+
+		err := suite.Ts.MergeTag(context.Background(), dto.MergeTagRequest{TargetID: 2}, 1)
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 2).Return(&model.Tag{ID: 2, Label: "golang"}, nil)
+		suite.MockTagRepo.EXPECT().MergeTag(gomock.Any(), 1, 2).Return(nil)
+
+		err := suite.Ts.MergeTag(context.Background(), dto.MergeTagRequest{TargetID: 2}, 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_DeleteTag() {
+	suite.Run("error when get tag", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(nil, errors.New("err from db"))
+
+		err := suite.Ts.DeleteTag(context.Background(), 1)
+		suite.Equal("err from db", err.Error())
+	})
+
+	suite.Run("error last tag of a post", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().DeleteTag(gomock.Any(), 1).Return(model.ErrLastTag)
+
+		err := suite.Ts.DeleteTag(context.Background(), 1)
+		suite.Equal(dto.ErrorConflict{Message: "tag is the last tag of some posts, tag them otherwise or merge it instead"}, err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetTag(gomock.Any(), 1).Return(&model.Tag{ID: 1, Label: "go"}, nil)
+		suite.MockTagRepo.EXPECT().DeleteTag(gomock.Any(), 1).Return(nil)
+
+		err := suite.Ts.DeleteTag(context.Background(), 1)
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_SweepOrphanTags() {
+	suite.Run("error", func() {
+		suite.MockTagRepo.EXPECT().DeleteOrphanTags(gomock.Any()).Return(int64(0), errors.New("err from db"))
+
+		err := suite.Ts.SweepOrphanTags(context.Background())
+		suite.Error(err)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().DeleteOrphanTags(gomock.Any()).Return(int64(2), nil)
+
+		err := suite.Ts.SweepOrphanTags(context.Background())
+		suite.NoError(err)
+	})
+}
+
+func (suite *TestTagServiceSuite) TestTagService_NormalizeTags() {
+	suite.Run("error when get tags", func() {
+		suite.MockTagRepo.EXPECT().GetAllTags(gomock.Any()).Return(nil, errors.New("err from db"))
+
+		merged, err := suite.Ts.NormalizeTags(context.Background())
+		suite.Error(err)
+		suite.Zero(merged)
+	})
+
+	suite.Run("error when merge", func() {
+		suite.MockTagRepo.EXPECT().GetAllTags(gomock.Any()).Return([]model.Tag{
+			{ID: 1, Label: "go", Slug: "go"},
+			{ID: 2, Label: "Go"},
+		}, nil)
+		suite.MockTagRepo.EXPECT().MergeTag(gomock.Any(), 2, 1).Return(errors.New("err from db"))
+
+		merged, err := suite.Ts.NormalizeTags(context.Background())
+		suite.Error(err)
+		suite.Zero(merged)
+	})
+
+	suite.Run("success", func() {
+		suite.MockTagRepo.EXPECT().GetAllTags(gomock.Any()).Return([]model.Tag{
+			{ID: 1, Label: "Go "},
+			{ID: 2, Label: "go"},
+			{ID: 3, Label: "sql", Slug: "sql"},
+			{ID: 4, Label: "GO"},
+		}, nil)
+		gomock.InOrder(
+			suite.MockTagRepo.EXPECT().MergeTag(gomock.Any(), 2, 1).Return(nil),
+			suite.MockTagRepo.EXPECT().MergeTag(gomock.Any(), 4, 1).Return(nil),
+			suite.MockTagRepo.EXPECT().UpdateTag(gomock.Any(), model.Tag{ID: 1, Label: "go", Slug: "go"}).Return(nil),
+		)
+
+		merged, err := suite.Ts.NormalizeTags(context.Background())
+		suite.NoError(err)
+		suite.Equal(2, merged)
+	})
+}
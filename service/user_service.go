@@ -0,0 +1,128 @@
+package service
+
+//go:generate mockgen -source $GOFILE -destination ../mock/service/mock_$GOFILE -package $GOPACKAGE
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	"github.com/elangreza14/assetfindr-test/model"
+	"gorm.io/gorm"
+)
+
+type (
+	IUserRepository interface {
+		CreateUser(ctx context.Context, req model.User) (*model.User, error)
+		GetUser(ctx context.Context, id int) (*model.User, error)
+		GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+		UpdateUserRole(ctx context.Context, id int, role string) error
+	}
+
+	UserService struct {
+		userRepository IUserRepository
+	}
+)
+
+func NewUserService(userRepository IUserRepository) *UserService {
+	return &UserService{
+		userRepository: userRepository,
+	}
+}
+
+// CreateUser creates the user, emails are compared in lower case so each of
+// them is only used once.
+func (us *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (*dto.GetUserResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	existingUser, err := us.userRepository.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if existingUser != nil {
+		return nil, dto.ErrorConflict{
+			Message: "email " + email + " is already used",
+		}
+	}
+
+	user, err := us.userRepository.CreateUser(ctx, model.User{
+		Name:  strings.TrimSpace(req.Name),
+		Email: email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := newGetUserResponse(*user)
+	return &res, nil
+}
+
+func (us *UserService) GetUser(ctx context.Context, id int) (*dto.GetUserResponse, error) {
+	user, err := us.userRepository.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ErrorNotFound{
+				EntityName: "user",
+				EntityID:   id,
+			}
+		}
+		return nil, err
+	}
+
+	res := newGetUserResponse(*user)
+	return &res, nil
+}
+
+// GetUserProfile is the user as shown to the principal, only the user
+// themselves and the users allowed to manage users see the email and the
+// role, everyone else gets the public profile.
+func (us *UserService) GetUserProfile(ctx context.Context, id int) (any, error) {
+	user, err := us.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if ok && (principal.UserID == user.ID || principal.Can(auth.PermManageUsers)) {
+		return user, nil
+	}
+
+	return dto.Author{
+		ID:   user.ID,
+		Name: user.Name,
+	}, nil
+}
+
+// UpdateUserRole changes the role of the user, only the users allowed to
+// manage users can.
+func (us *UserService) UpdateUserRole(ctx context.Context, req dto.UpdateUserRoleRequest, id int) error {
+	err := authorize(ctx, auth.PermManageUsers)
+	if err != nil {
+		return err
+	}
+
+	_, err = us.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = us.userRepository.UpdateUserRole(ctx, id, req.Role)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func newGetUserResponse(user model.User) dto.GetUserResponse {
+	return dto.GetUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/elangreza14/assetfindr-test/auth"
+	"github.com/elangreza14/assetfindr-test/dto"
+	gomockService "github.com/elangreza14/assetfindr-test/mock/service"
+	"github.com/elangreza14/assetfindr-test/model"
+	. "github.com/elangreza14/assetfindr-test/service"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type TestUserServiceSuite struct {
+	suite.Suite
+
+	MockUserRepo *gomockService.MockIUserRepository
+	Us           *UserService
+	Ctrl         *gomock.Controller
+}
+
+func (suite *TestUserServiceSuite) SetupSuite() {
+	suite.Ctrl = gomock.NewController(suite.T())
+	suite.MockUserRepo = gomockService.NewMockIUserRepository(suite.Ctrl)
+	suite.Us = NewUserService(suite.MockUserRepo)
+}
+
+func (suite *TestUserServiceSuite) TearDownSuite() {
+	suite.Ctrl.Finish()
+}
+
+func TestUserServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TestUserServiceSuite))
+}
+
+func (suite *TestUserServiceSuite) TestUserService_CreateUser() {
+	req := dto.CreateUserRequest{Name: "jane", Email: "Jane@Example.com"}
+
+	suite.Run("error when get user by email", func() {
+		suite.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(nil, errors.New("err from db"))
+
+		res, err := suite.Us.CreateUser(context.Background(), req)
+		suite.Error(err)
+		suite.Nil(res)
+	})
+
+	suite.Run("error email already used", func() {
+		suite.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(&model.User{ID: 1}, nil)
+
+		res, err := suite.Us.CreateUser(context.Background(), req)
+		suite.ErrorIs(err, dto.ErrorConflict{Message: "email jane@example.com is already used"})
+		suite.Nil(res)
+	})
+
+	suite.Run("success", func() {
+		suite.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(nil, gorm.ErrRecordNotFound)
+		suite.MockUserRepo.EXPECT().CreateUser(gomock.Any(), model.User{Name: "jane", Email: "jane@example.com"}).
+			Return(&model.User{ID: 7, Name: "jane", Email: "jane@example.com"}, nil)
+
+		res, err := suite.Us.CreateUser(context.Background(), req)
+		suite.NoError(err)
+		suite.Equal(7, res.ID)
+		suite.Equal("jane@example.com", res.Email)
+	})
+}
+
+func (suite *TestUserServiceSuite) TestUserService_GetUser() {
+	suite.Run("error not found", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := suite.Us.GetUser(context.Background(), 7)
+		suite.Nil(res)
+		suite.Equal("cannot find user with id 7", err.Error())
+	})
+
+	suite.Run("success", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(&model.User{ID: 7, Name: "jane"}, nil)
+
+		res, err := suite.Us.GetUser(context.Background(), 7)
+		suite.NoError(err)
+		suite.Equal("jane", res.Name)
+	})
+}
+
+func (suite *TestUserServiceSuite) TestUserService_GetUserProfile() {
+	user := &model.User{ID: 7, Name: "jane", Email: "jane@example.com", Role: "author"}
+
+	suite.Run("error not found", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(nil, gorm.ErrRecordNotFound)
+
+		res, err := suite.Us.GetUserProfile(context.Background(), 7)
+		suite.Nil(res)
+		suite.Equal("cannot find user with id 7", err.Error())
+	})
+
+	suite.Run("success anonymous", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(user, nil)
+
+		res, err := suite.Us.GetUserProfile(context.Background(), 7)
+		suite.NoError(err)
+		suite.Equal(dto.Author{ID: 7, Name: "jane"}, res)
+	})
+
+	suite.Run("success another user", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(user, nil)
+
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 2, Role: auth.RoleEditor})
+		res, err := suite.Us.GetUserProfile(ctx, 7)
+		suite.NoError(err)
+		suite.Equal(dto.Author{ID: 7, Name: "jane"}, res)
+	})
+
+	suite.Run("success themselves", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(user, nil)
+
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 7, Role: auth.RoleAuthor})
+		res, err := suite.Us.GetUserProfile(ctx, 7)
+		suite.NoError(err)
+		suite.Equal(&dto.GetUserResponse{ID: 7, Name: "jane", Email: "jane@example.com", Role: "author"}, res)
+	})
+
+	suite.Run("success admin", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(user, nil)
+
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 1, Role: auth.RoleAdmin})
+		res, err := suite.Us.GetUserProfile(ctx, 7)
+		suite.NoError(err)
+		suite.Equal(&dto.GetUserResponse{ID: 7, Name: "jane", Email: "jane@example.com", Role: "author"}, res)
+	})
+}
+
+func (suite *TestUserServiceSuite) TestUserService_UpdateUserRole() {
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 1, Role: auth.RoleAdmin})
+
+	suite.Run("error by an editor", func() {
+		editor := auth.WithPrincipal(context.Background(), auth.Principal{UserID: 2, Role: auth.RoleEditor})
+
+		err := suite.Us.UpdateUserRole(editor, dto.UpdateUserRoleRequest{Role: "admin"}, 2)
+		suite.ErrorIs(err, dto.ErrorForbidden{Message: "the users:manage permission is required"})
+	})
+
+	suite.Run("error not found", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(nil, gorm.ErrRecordNotFound)
+
+		err := suite.Us.UpdateUserRole(admin, dto.UpdateUserRoleRequest{Role: "editor"}, 7)
+		suite.Equal("cannot find user with id 7", err.Error())
+	})
+
+	suite.Run("success", func() {
+		suite.MockUserRepo.EXPECT().GetUser(gomock.Any(), 7).Return(&model.User{ID: 7, Role: "author"}, nil)
+		suite.MockUserRepo.EXPECT().UpdateUserRole(gomock.Any(), 7, "editor").Return(nil)
+
+		err := suite.Us.UpdateUserRole(admin, dto.UpdateUserRoleRequest{Role: "editor"}, 7)
+		suite.NoError(err)
+	})
+}
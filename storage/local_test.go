@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/elangreza14/assetfindr-test/storage"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLocalStoreSuite struct {
+	suite.Suite
+
+	dir   string
+	store *LocalStore
+}
+
+func (suite *TestLocalStoreSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+
+	store, err := NewLocalStore(suite.dir)
+	suite.Require().NoError(err)
+	suite.store = store
+}
+
+func TestLocalStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(TestLocalStoreSuite))
+}
+
+func (suite *TestLocalStoreSuite) read(key string, offset, length int64) string {
+	r, err := suite.store.Get(context.Background(), key, offset, length)
+	suite.Require().NoError(err)
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	suite.Require().NoError(err)
+
+	return string(body)
+}
+
+func (suite *TestLocalStoreSuite) TestLocalStore_PutGet() {
+	err := suite.store.Put(context.Background(), "posts/1/abc", strings.NewReader("hello world"), 11, "text/plain")
+	suite.NoError(err)
+
+	suite.Equal("hello world", suite.read("posts/1/abc", 0, -1))
+	suite.Equal("world", suite.read("posts/1/abc", 6, -1))
+	suite.Equal("lo w", suite.read("posts/1/abc", 3, 4))
+
+	_, err = os.Stat(filepath.Join(suite.dir, "posts", "1", "abc"))
+	suite.NoError(err)
+}
+
+func (suite *TestLocalStoreSuite) TestLocalStore_PutSizeMismatch() {
+	err := suite.store.Put(context.Background(), "posts/1/abc", strings.NewReader("hello"), 11, "text/plain")
+	suite.Error(err)
+
+	_, err = suite.store.Get(context.Background(), "posts/1/abc", 0, -1)
+	suite.ErrorIs(err, ErrBlobNotFound)
+
+	entries, err := os.ReadDir(filepath.Join(suite.dir, "posts", "1"))
+	suite.NoError(err)
+	suite.Empty(entries)
+}
+
+func (suite *TestLocalStoreSuite) TestLocalStore_InvalidKey() {
+	for _, key := range []string{"../secret", "/etc/passwd", "posts/../../x", `posts\1`, ""} {
+		err := suite.store.Put(context.Background(), key, strings.NewReader(""), 0, "text/plain")
+		suite.Error(err, key)
+	}
+}
+
+func (suite *TestLocalStoreSuite) TestLocalStore_Delete() {
+	err := suite.store.Put(context.Background(), "posts/1/abc", strings.NewReader("hello"), 5, "text/plain")
+	suite.Require().NoError(err)
+
+	suite.NoError(suite.store.Delete(context.Background(), "posts/1/abc"))
+
+	_, err = suite.store.Get(context.Background(), "posts/1/abc", 0, -1)
+	suite.ErrorIs(err, ErrBlobNotFound)
+
+	suite.NoError(suite.store.Delete(context.Background(), "posts/1/abc"))
+}
@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// emptyPayloadHash is the sha256 of an empty body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// unsignedPayload skips the hash of the uploaded blobs, which would need
+	// to be read twice. TLS protects them in transit instead.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+type (
+	// S3Store stores the blobs as the objects of a bucket of an S3 compatible
+	// service, signing its requests with AWS Signature Version 4.
+	S3Store struct {
+		endpoint        *url.URL
+		bucket          string
+		region          string
+		accessKeyID     string
+		secretAccessKey string
+		virtualHosted   bool
+		client          *http.Client
+		now             func() time.Time
+	}
+
+	S3StoreOption func(*S3Store)
+)
+
+// WithS3Region sets the region of the bucket, us-east-1 by default.
+func WithS3Region(region string) S3StoreOption {
+	return func(ss *S3Store) {
+		ss.region = region
+	}
+}
+
+// WithS3Credentials signs the requests with the access key.
+func WithS3Credentials(accessKeyID, secretAccessKey string) S3StoreOption {
+	return func(ss *S3Store) {
+		ss.accessKeyID = accessKeyID
+		ss.secretAccessKey = secretAccessKey
+	}
+}
+
+// WithS3VirtualHostedStyle addresses the bucket as a sub domain of the
+// endpoint, as AWS does, instead of as the first segment of the path, as most
+// S3 compatible services do.
+func WithS3VirtualHostedStyle() S3StoreOption {
+	return func(ss *S3Store) {
+		ss.virtualHosted = true
+	}
+}
+
+// WithS3HTTPClient sends the requests with the client instead of the default
+// one.
+func WithS3HTTPClient(client *http.Client) S3StoreOption {
+	return func(ss *S3Store) {
+		ss.client = client
+	}
+}
+
+// WithS3Clock sets the clock dating the signatures.
+func WithS3Clock(now func() time.Time) S3StoreOption {
+	return func(ss *S3Store) {
+		ss.now = now
+	}
+}
+
+func NewS3Store(endpoint, bucket string, opts ...S3StoreOption) (*S3Store, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q must be an absolute url", endpoint)
+	}
+
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+
+	ss := &S3Store{
+		endpoint: u,
+		bucket:   bucket,
+		region:   "us-east-1",
+		client:   http.DefaultClient,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(ss)
+	}
+
+	return ss, nil
+}
+
+// Put uploads the blob read from r as the object of the key, replacing any
+// object of the key.
+func (ss *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := ss.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := ss.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ss.responseError(req, res)
+	}
+
+	return nil
+}
+
+// Get downloads length bytes of the object from offset, or up to its end when
+// length is negative.
+func (ss *S3Store) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	req, err := ss.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case length == 0:
+		return io.NopCloser(strings.NewReader("")), nil
+	case length > 0:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	case offset > 0:
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := ss.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return res.Body, nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, ErrBlobNotFound
+	default:
+		defer res.Body.Close()
+		return nil, ss.responseError(req, res)
+	}
+}
+
+// Delete deletes the object of the key, deleting an object that does not
+// exist is not an error.
+func (ss *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := ss.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := ss.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return ss.responseError(req, res)
+	}
+}
+
+func (ss *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *ss.endpoint
+	path := "/" + key
+	if ss.virtualHosted {
+		u.Host = ss.bucket + "." + u.Host
+	} else {
+		path = "/" + ss.bucket + path
+	}
+	u.RawPath = strings.TrimSuffix(ss.endpoint.EscapedPath(), "/") + uriEncode(path, false)
+	u.Path = strings.TrimSuffix(ss.endpoint.Path, "/") + path
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (ss *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	ss.sign(req, payloadHash)
+	return ss.client.Do(req)
+}
+
+// sign adds the AWS Signature Version 4 of the request to its headers, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (ss *S3Store) sign(req *http.Request, payloadHash string) {
+	now := ss.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + ss.region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := strings.Builder{}
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + ss.secretAccessKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		ss.accessKeyID, scope, signedHeaders, signature))
+}
+
+func (ss *S3Store) responseError(req *http.Request, res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, strings.TrimSpace(string(body)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts the query by name and value, each of them URI encoded.
+func canonicalQuery(query url.Values) string {
+	params := []string{}
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent encodes every byte of s but the unreserved characters,
+// and the slashes unless encodeSlash is set, as AWS expects.
+func uriEncode(s string, encodeSlash bool) string {
+	encoded := strings.Builder{}
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
@@ -0,0 +1,8 @@
+// Package storage holds the blob stores keeping the content of the
+// attachments, either on the local disk or in an S3 compatible bucket.
+package storage
+
+import "errors"
+
+// ErrBlobNotFound is returned when reading a blob that is not stored.
+var ErrBlobNotFound = errors.New("blob not found")